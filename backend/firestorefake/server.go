@@ -0,0 +1,390 @@
+// Package firestorefake implements an in-memory fake of the subset of the Firestore REST API
+// used by this project: runQuery ordered by __name__ with startAt and limit, collection
+// listing with pageSize/pageToken/mask, single document reads and document creation.
+// It also stands in for the OAuth2 token endpoint used with service account keys.
+//
+// It can be mounted in an httptest.Server, or run standalone via utils/firestore_fake.
+// Point the backend, seeder or connection checker at it with FIRESTORE_BASE_URL=<server URL>/v1/.
+package firestorefake
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiPrefix is the path prefix of every Firestore REST endpoint.
+const apiPrefix = "/v1/"
+
+// Document is a stored Firestore document in REST form.
+type Document struct {
+	Name       string         `json:"name"`
+	Fields     map[string]any `json:"fields"`
+	CreateTime string         `json:"createTime"`
+	UpdateTime string         `json:"updateTime"`
+}
+
+// Server is an in-memory Firestore REST fake. It is safe for concurrent use.
+type Server struct {
+	mu sync.Mutex
+	// collections maps a full collection path ("projects/.../documents/a/b/c") to its documents keyed by ID.
+	collections map[string]map[string]*Document
+	// failures holds statuses to answer the next requests with, to simulate outages.
+	failures []int
+
+	// Token endpoint state, see token.go.
+	requireAuth   bool
+	verifyKey     *rsa.PublicKey
+	tokens        map[string]issuedToken
+	tokenCount    int
+	tokenLifetime time.Duration
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// New creates an empty fake Firestore server.
+func New() *Server {
+	return &Server{
+		collections:   make(map[string]map[string]*Document),
+		tokens:        make(map[string]issuedToken),
+		tokenLifetime: time.Hour,
+	}
+}
+
+// DocumentsRoot returns the documents root path of a project's default database.
+func DocumentsRoot(projectID string) string {
+	return fmt.Sprintf("projects/%s/databases/(default)/documents", projectID)
+}
+
+// AddDocument stores a document with plain string fields in a collection and returns its full name.
+// An empty id gets a random 20-character ID, like Firestore's auto IDs.
+func (server *Server) AddDocument(collectionPath, id string, fields map[string]string) string {
+	restFields := make(map[string]any, len(fields))
+	for key, value := range fields {
+		restFields[key] = map[string]any{"stringValue": value}
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return server.store(collectionPath, id, restFields).Name
+}
+
+// DeleteDocument removes a document from a collection, if it exists.
+func (server *Server) DeleteDocument(collectionPath, id string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	delete(server.collections[collectionPath], id)
+}
+
+// Documents returns the documents of a collection ordered by name.
+func (server *Server) Documents(collectionPath string) []Document {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	docs := server.sorted(collectionPath)
+	result := make([]Document, len(docs))
+	for i, doc := range docs {
+		result[i] = *doc
+	}
+	return result
+}
+
+// FailNext makes the next len(statuses) requests fail with the given HTTP statuses, in order.
+func (server *Server) FailNext(statuses ...int) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.failures = append(server.failures, statuses...)
+}
+
+// ServeHTTP dispatches a Firestore REST request.
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if status, failing := server.nextFailure(); failing {
+		writeError(w, status, "injected failure")
+		return
+	}
+
+	if r.URL.Path == TokenPath {
+		server.issueToken(w, r)
+		return
+	}
+	if !server.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "missing or invalid access token")
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, apiPrefix)
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown path")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(path, ":runQuery"):
+		server.runQuery(w, r, strings.TrimSuffix(path, ":runQuery"))
+	case r.Method == http.MethodPost && isCollectionPath(path):
+		server.createDocument(w, r, path)
+	case r.Method == http.MethodGet && isCollectionPath(path):
+		server.listDocuments(w, r, path)
+	case r.Method == http.MethodGet:
+		server.getDocument(w, path)
+	default:
+		writeError(w, http.StatusNotFound, "unsupported request")
+	}
+}
+
+// runQuery answers a structuredQuery over a single collection, ordered by __name__.
+func (server *Server) runQuery(w http.ResponseWriter, r *http.Request, parent string) {
+	var request struct {
+		StructuredQuery struct {
+			From []struct {
+				CollectionID string `json:"collectionId"`
+			} `json:"from"`
+			OrderBy []struct {
+				Field struct {
+					FieldPath string `json:"fieldPath"`
+				} `json:"field"`
+				Direction string `json:"direction"`
+			} `json:"orderBy"`
+			StartAt *struct {
+				Values []struct {
+					ReferenceValue string `json:"referenceValue"`
+				} `json:"values"`
+				Before bool `json:"before"`
+			} `json:"startAt"`
+			Limit int `json:"limit"`
+		} `json:"structuredQuery"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid query: "+err.Error())
+		return
+	}
+	query := request.StructuredQuery
+	if len(query.From) != 1 {
+		writeError(w, http.StatusBadRequest, "exactly one collection is supported in from")
+		return
+	}
+	for _, order := range query.OrderBy {
+		if order.Field.FieldPath != "__name__" || order.Direction == "DESCENDING" {
+			writeError(w, http.StatusBadRequest, "only ascending __name__ ordering is supported")
+			return
+		}
+	}
+
+	server.mu.Lock()
+	docs := server.sorted(parent + "/" + query.From[0].CollectionID)
+	server.mu.Unlock()
+
+	if query.StartAt != nil && len(query.StartAt.Values) > 0 {
+		cursor := query.StartAt.Values[0].ReferenceValue
+		inclusive := query.StartAt.Before
+		docs = docs[sort.Search(len(docs), func(i int) bool {
+			if inclusive {
+				return docs[i].Name >= cursor
+			}
+			return docs[i].Name > cursor
+		}):]
+	}
+	if query.Limit > 0 && len(docs) > query.Limit {
+		docs = docs[:query.Limit]
+	}
+
+	readTime := timestamp()
+	results := make([]map[string]any, 0, len(docs))
+	for _, doc := range docs {
+		results = append(results, map[string]any{"document": doc, "readTime": readTime})
+	}
+	if len(results) == 0 {
+		// Firestore answers an empty query with a single result that has no document.
+		results = append(results, map[string]any{"readTime": readTime})
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// listDocuments answers a paged collection listing, honoring pageSize, pageToken and mask.fieldPaths.
+func (server *Server) listDocuments(w http.ResponseWriter, r *http.Request, collectionPath string) {
+	params := r.URL.Query()
+	pageSize, _ := strconv.Atoi(params.Get("pageSize"))
+	offset := 0
+	if token := params.Get("pageToken"); token != "" {
+		parsed, err := strconv.Atoi(token)
+		if err != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, "invalid pageToken")
+			return
+		}
+		offset = parsed
+	}
+
+	server.mu.Lock()
+	docs := server.sorted(collectionPath)
+	server.mu.Unlock()
+
+	docs = docs[min(offset, len(docs)):]
+	nextPageToken := ""
+	if pageSize > 0 && len(docs) > pageSize {
+		docs = docs[:pageSize]
+		nextPageToken = strconv.Itoa(offset + pageSize)
+	}
+
+	mask := params["mask.fieldPaths"]
+	page := make([]Document, len(docs))
+	for i, doc := range docs {
+		page[i] = applyMask(*doc, mask)
+	}
+
+	response := map[string]any{}
+	if len(page) > 0 {
+		response["documents"] = page
+	}
+	if nextPageToken != "" {
+		response["nextPageToken"] = nextPageToken
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// createDocument stores the posted document under an auto-generated ID (or ?documentId=).
+func (server *Server) createDocument(w http.ResponseWriter, r *http.Request, collectionPath string) {
+	var body struct {
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid document: "+err.Error())
+		return
+	}
+
+	server.mu.Lock()
+	doc := *server.store(collectionPath, r.URL.Query().Get("documentId"), body.Fields)
+	server.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, doc)
+}
+
+// getDocument answers a read of a single document by its full name.
+func (server *Server) getDocument(w http.ResponseWriter, name string) {
+	collectionPath, id := splitName(name)
+
+	server.mu.Lock()
+	doc, exists := server.collections[collectionPath][id]
+	var found Document
+	if exists {
+		found = *doc
+	}
+	server.mu.Unlock()
+
+	if !exists {
+		writeError(w, http.StatusNotFound, "document not found: "+name)
+		return
+	}
+	writeJSON(w, http.StatusOK, found)
+}
+
+// store saves a document; the caller must hold server.mu.
+func (server *Server) store(collectionPath, id string, fields map[string]any) *Document {
+	if id == "" {
+		id = autoID()
+	}
+	if server.collections[collectionPath] == nil {
+		server.collections[collectionPath] = make(map[string]*Document)
+	}
+	now := timestamp()
+	doc := &Document{
+		Name:       collectionPath + "/" + id,
+		Fields:     fields,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	server.collections[collectionPath][id] = doc
+	return doc
+}
+
+// sorted returns a collection's documents ordered by name; the caller must hold server.mu.
+func (server *Server) sorted(collectionPath string) []*Document {
+	docs := make([]*Document, 0, len(server.collections[collectionPath]))
+	for _, doc := range server.collections[collectionPath] {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Name < docs[j].Name })
+	return docs
+}
+
+// nextFailure pops the next injected failure status, if any.
+func (server *Server) nextFailure() (int, bool) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if len(server.failures) == 0 {
+		return 0, false
+	}
+	status := server.failures[0]
+	server.failures = server.failures[1:]
+	return status, true
+}
+
+// isCollectionPath reports whether a resource path names a collection rather than a document.
+// Below "documents", collection paths have an odd number of segments.
+func isCollectionPath(path string) bool {
+	_, relative, found := strings.Cut(path, "/documents/")
+	return found && strings.Count(relative, "/")%2 == 0
+}
+
+// splitName splits a document name into its collection path and document ID.
+func splitName(name string) (string, string) {
+	i := strings.LastIndex(name, "/")
+	if i < 0 {
+		return "", name
+	}
+	return name[:i], name[i+1:]
+}
+
+// applyMask returns a copy of a document with only the masked fields, or all fields if mask is empty.
+func applyMask(doc Document, mask []string) Document {
+	if len(mask) == 0 {
+		return doc
+	}
+	fields := make(map[string]any, len(mask))
+	for _, fieldPath := range mask {
+		if value, exists := doc.Fields[fieldPath]; exists {
+			fields[fieldPath] = value
+		}
+	}
+	doc.Fields = fields
+	return doc
+}
+
+// autoID generates a random 20-character document ID.
+func autoID() string {
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	b := make([]byte, 20)
+	for i := range b {
+		b[i] = charset[rand.IntN(len(charset))]
+	}
+	return string(b)
+}
+
+// timestamp returns the current time in Firestore's RFC 3339 format.
+func timestamp() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes an error in the shape Firestore uses.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]any{"code": status, "message": message, "status": http.StatusText(status)},
+	})
+}
@@ -0,0 +1,178 @@
+package firestorefake
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TokenPath is the path of the OAuth2 token endpoint stand-in. Use <server URL>/token as the
+// token_uri of a test service account key.
+const TokenPath = "/token"
+
+// issuedToken is an access token handed out by the token endpoint.
+type issuedToken struct {
+	clientEmail string
+	expiry      time.Time
+}
+
+// RequireAuth makes the Firestore endpoints reject requests without a valid Bearer token issued
+// by the token endpoint. If verifyKey is not nil, JWT assertion signatures are checked against it.
+func (server *Server) RequireAuth(verifyKey *rsa.PublicKey) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.requireAuth = true
+	server.verifyKey = verifyKey
+}
+
+// SetTokenLifetime changes the expires_in of newly issued access tokens.
+func (server *Server) SetTokenLifetime(lifetime time.Duration) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.tokenLifetime = lifetime
+}
+
+// RevokeTokens invalidates every access token issued so far.
+func (server *Server) RevokeTokens() {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.tokens = make(map[string]issuedToken)
+}
+
+// TokenRequests returns how many access tokens the token endpoint has issued.
+func (server *Server) TokenRequests() int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return server.tokenCount
+}
+
+// issueToken exchanges a JWT bearer assertion for an access token, like Google's token endpoint.
+func (server *Server) issueToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "token endpoint only accepts POST")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid form")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+		writeTokenError(w, "unsupported_grant_type")
+		return
+	}
+
+	server.mu.Lock()
+	verifyKey := server.verifyKey
+	server.mu.Unlock()
+
+	claims, err := parseAssertion(r.PostForm.Get("assertion"), verifyKey)
+	if err != nil {
+		writeTokenError(w, "invalid_grant: "+err.Error())
+		return
+	}
+
+	server.mu.Lock()
+	server.tokenCount++
+	token := fmt.Sprintf("fake-token-%d", server.tokenCount)
+	lifetime := server.tokenLifetime
+	server.tokens[token] = issuedToken{clientEmail: claims.Issuer, expiry: time.Now().Add(lifetime)}
+	server.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   int(lifetime.Seconds()),
+	})
+}
+
+// authorized reports whether a Firestore request carries an acceptable Bearer token.
+func (server *Server) authorized(r *http.Request) bool {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if !server.requireAuth {
+		return true
+	}
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return false
+	}
+	issued, exists := server.tokens[token]
+	return exists && time.Now().Before(issued.expiry)
+}
+
+// assertionClaims are the JWT claims the token endpoint checks.
+type assertionClaims struct {
+	Issuer   string `json:"iss"`
+	Scope    string `json:"scope"`
+	Audience string `json:"aud"`
+	Expiry   int64  `json:"exp"`
+}
+
+// parseAssertion decodes an RS256 JWT assertion, checking its claims and, if verifyKey is set, its signature.
+func parseAssertion(assertion string, verifyKey *rsa.PublicKey) (*assertionClaims, error) {
+	parts := strings.Split(assertion, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("assertion is not a JWT")
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Algorithm != "RS256" {
+		return nil, fmt.Errorf("unsupported algorithm %q", header.Algorithm)
+	}
+
+	var claims assertionClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if claims.Issuer == "" || claims.Scope == "" || claims.Audience == "" {
+		return nil, fmt.Errorf("assertion is missing iss, scope or aud")
+	}
+	if time.Now().Unix() >= claims.Expiry {
+		return nil, fmt.Errorf("assertion has expired")
+	}
+
+	if verifyKey != nil {
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature encoding")
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(verifyKey, crypto.SHA256, digest[:], signature); err != nil {
+			return nil, fmt.Errorf("invalid signature")
+		}
+	}
+	return &claims, nil
+}
+
+// decodeSegment decodes a base64url JSON segment of a JWT.
+func decodeSegment(segment string, target any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("invalid JWT segment encoding")
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("invalid JWT segment: %w", err)
+	}
+	return nil
+}
+
+// writeTokenError writes an OAuth2 error response.
+func writeTokenError(w http.ResponseWriter, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": description})
+}
@@ -0,0 +1,246 @@
+package game
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// FirestoreScope is the OAuth2 scope granting read/write access to Firestore.
+const FirestoreScope = "https://www.googleapis.com/auth/datastore"
+
+// defaultTokenURI is Google's OAuth2 token endpoint, used when the key does not name one.
+const defaultTokenURI = "https://oauth2.googleapis.com/token"
+
+// tokenRefreshMargin is how long before expiry a cached access token is refreshed.
+const tokenRefreshMargin = time.Minute
+
+// ServiceAccountKey holds the fields of a service account JSON key (serviceAccountKey.json) used to sign in.
+type ServiceAccountKey struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// LoadServiceAccountKey reads a service account JSON key from disk.
+func LoadServiceAccountKey(path string) (*ServiceAccountKey, error) {
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account key: %w", err)
+	}
+	var key ServiceAccountKey
+	if err := json.Unmarshal(fileData, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse service account key: %w", err)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("service account key %s is missing client_email or private_key", path)
+	}
+	return &key, nil
+}
+
+// TokenSource obtains OAuth2 access tokens for a service account using signed JWT assertions
+// (RFC 7523). Tokens are cached and refreshed shortly before they expire. A nil *TokenSource
+// is valid and leaves requests unauthenticated.
+type TokenSource struct {
+	key        *ServiceAccountKey
+	privateKey *rsa.PrivateKey
+	scope      string
+	client     *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewTokenSource creates a TokenSource for a service account key and OAuth2 scopes.
+func NewTokenSource(key *ServiceAccountKey, scopes ...string) (*TokenSource, error) {
+	privateKey, err := parsePrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenSource{
+		key:        key,
+		privateKey: privateKey,
+		scope:      strings.Join(scopes, " "),
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// NewFirestoreAuthFromEnv creates a Firestore TokenSource from the service account key named by
+// GOOGLE_APPLICATION_CREDENTIALS, falling back to serviceAccountKey.json in the working directory
+// when talking to the real Firestore. It returns nil, and Firestore is accessed anonymously,
+// when no key is found.
+func NewFirestoreAuthFromEnv() (*TokenSource, error) {
+	_ = godotenv.Load()
+
+	keyPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if keyPath == "" {
+		// Emulators and fake servers don't need credentials, so don't pick up a stray key file for them.
+		if FirestoreBaseURL() != defaultFirestoreBaseURL {
+			return nil, nil
+		}
+		keyPath = "serviceAccountKey.json"
+		if _, err := os.Stat(keyPath); errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+	}
+
+	key, err := LoadServiceAccountKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewTokenSource(key, FirestoreScope)
+}
+
+// Token returns a valid access token, requesting a new one if the cached token is about to expire.
+func (ts *TokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token != "" && time.Until(ts.expiry) > tokenRefreshMargin {
+		return ts.token, nil
+	}
+
+	token, expiresIn, err := ts.fetchToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	ts.token = token
+	ts.expiry = time.Now().Add(expiresIn)
+	return ts.token, nil
+}
+
+// Invalidate drops the cached token, e.g. after the server rejected it.
+func (ts *TokenSource) Invalidate() {
+	if ts == nil {
+		return
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.token = ""
+}
+
+// Authorize adds a Bearer token to the request. It does nothing on a nil TokenSource.
+func (ts *TokenSource) Authorize(request *http.Request) error {
+	if ts == nil {
+		return nil
+	}
+	token, err := ts.Token(request.Context())
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// fetchToken exchanges a freshly signed JWT assertion for an access token.
+func (ts *TokenSource) fetchToken(ctx context.Context) (string, time.Duration, error) {
+	tokenURI := ts.key.TokenURI
+	if tokenURI == "" {
+		tokenURI = defaultTokenURI
+	}
+
+	assertion, err := ts.signAssertion(tokenURI, time.Now())
+	if err != nil {
+		return "", 0, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to build token request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := ts.client.Do(request)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to request access token: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("token endpoint returned status: %s", response.Status)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return "", 0, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return "", 0, fmt.Errorf("token endpoint returned no access token")
+	}
+	return body.AccessToken, time.Duration(body.ExpiresIn) * time.Second, nil
+}
+
+// signAssertion builds and RS256-signs the JWT assertion for the token request.
+func (ts *TokenSource) signAssertion(audience string, now time.Time) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT", "kid": ts.key.PrivateKeyID}
+	claims := map[string]any{
+		"iss":   ts.key.ClientEmail,
+		"scope": ts.scope,
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(nil, ts.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT assertion: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey decodes a PEM RSA private key in PKCS#8 or PKCS#1 form.
+func parsePrivateKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, fmt.Errorf("service account private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("service account private key is not an RSA key")
+		}
+		return rsaKey, nil
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service account private key: %w", err)
+	}
+	return key, nil
+}
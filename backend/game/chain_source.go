@@ -0,0 +1,160 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ChainLink is a named WordSource taking part in a ChainSource.
+type ChainLink struct {
+	Name   string
+	Source WordSource
+}
+
+// LinkHealth reports the health of a single link in a ChainSource.
+type LinkHealth struct {
+	Name           string    `json:"name"`
+	Healthy        bool      `json:"healthy"`
+	UnhealthyUntil time.Time `json:"unhealthy_until,omitzero"`
+	LastError      string    `json:"last_error,omitempty"`
+}
+
+// ChainSource is a WordSource that tries its links in order and fails over to the next one.
+// A link that fails is skipped until its cool-off expires, after which the next request probes it again.
+type ChainSource struct {
+	mu       sync.Mutex
+	links    []ChainLink
+	health   []LinkHealth
+	cooldown time.Duration
+	timeout  time.Duration
+}
+
+var (
+	_ WordSource     = (*ChainSource)(nil)
+	_ CategoryLister = (*ChainSource)(nil)
+	_ HealthReporter = (*ChainSource)(nil)
+)
+
+// NewChainSource creates a ChainSource over the given links.
+// cooldown is how long a failed link is skipped; timeout bounds a single attempt (zero means no limit).
+func NewChainSource(cooldown, timeout time.Duration, links ...ChainLink) *ChainSource {
+	health := make([]LinkHealth, len(links))
+	for i, link := range links {
+		health[i] = LinkHealth{Name: link.Name, Healthy: true}
+	}
+	return &ChainSource{links: links, health: health, cooldown: cooldown, timeout: timeout}
+}
+
+// FetchRandomWord fetches a word from the first healthy link that succeeds.
+// The returned record's Source is set to the name of the link that served it.
+// A link that answers without a matching word ends the search with ErrNoWords: the other links
+// are fallbacks for outages, not extra word lists. Such a link is not marked unhealthy.
+func (source *ChainSource) FetchRandomWord(ctx context.Context, query WordQuery) (*WordRecord, error) {
+	var errs []error
+	var skipped []int
+
+	for i, link := range source.links {
+		if !source.available(i) {
+			skipped = append(skipped, i)
+			continue
+		}
+		word, err := source.try(ctx, i, link, query)
+		if err == nil || errors.Is(err, ErrNoWords) {
+			return word, err
+		}
+		errs = append(errs, err)
+	}
+
+	// Every healthy link failed: give the cooling-off ones a chance rather than failing the request.
+	for _, i := range skipped {
+		if ctx.Err() != nil {
+			break
+		}
+		word, err := source.try(ctx, i, source.links[i], query)
+		if err == nil || errors.Is(err, ErrNoWords) {
+			return word, err
+		}
+		errs = append(errs, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no word sources configured")
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Categories lists the categories of the first available link that can list them.
+func (source *ChainSource) Categories(ctx context.Context, lang string) ([]CategoryCount, error) {
+	var errs []error
+	for i, link := range source.links {
+		lister, ok := link.Source.(CategoryLister)
+		if !ok || !source.available(i) {
+			continue
+		}
+		categories, err := lister.Categories(ctx, lang)
+		if err == nil {
+			return categories, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", link.Name, err))
+	}
+	if len(errs) == 0 {
+		return []CategoryCount{}, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Health returns a snapshot of the health of every link in the chain.
+func (source *ChainSource) Health() []LinkHealth {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	now := time.Now()
+	health := make([]LinkHealth, len(source.health))
+	for i, h := range source.health {
+		h.Healthy = !now.Before(h.UnhealthyUntil)
+		health[i] = h
+	}
+	return health
+}
+
+// available reports whether the link at index i is healthy or due for a probe.
+func (source *ChainSource) available(i int) bool {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	return !time.Now().Before(source.health[i].UnhealthyUntil)
+}
+
+// try fetches a word from a single link and records the outcome in its health.
+// A failure caused by the caller giving up, or by the link having no matching word,
+// does not mark the link unhealthy.
+func (source *ChainSource) try(ctx context.Context, i int, link ChainLink, query WordQuery) (*WordRecord, error) {
+	attemptCtx := ctx
+	if source.timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, source.timeout)
+		defer cancel()
+	}
+	word, err := link.Source.FetchRandomWord(attemptCtx, query)
+
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	if err != nil {
+		if ctx.Err() == nil && !errors.Is(err, ErrNoWords) {
+			source.health[i].Healthy = false
+			source.health[i].UnhealthyUntil = time.Now().Add(source.cooldown)
+			source.health[i].LastError = err.Error()
+		}
+		return nil, fmt.Errorf("%s: %w", link.Name, err)
+	}
+	source.health[i] = LinkHealth{Name: link.Name, Healthy: true}
+	word.Source = link.Name
+	return word, nil
+}
@@ -1,13 +1,17 @@
 package game
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"math/rand"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 	"time"
+
 	"github.com/joho/godotenv"
 )
 
@@ -16,29 +20,42 @@ type WordRecord struct {
 	Text     string `json:"text"`
 	Hint     string `json:"hint"`
 	Language string `json:"language"` // e.g., "en", "pl", "uk"
+	Category string `json:"category"` // e.g., "animals"; empty if the word has no category
+	Source   string `json:"source"`   // name of the word source that served the word
 }
 
-// FirestoreQueryResult represents the result of a Firestore query.
-type FirestoreQueryResult []struct {
-	Document struct {
-		Name   string `json:"name"`
-		Fields struct {
-			Text struct {
-				StringValue string `json:"stringValue"`
-			} `json:"text"`
-			Hint struct {
-				StringValue string `json:"stringValue"`
-			} `json:"hint"`
-		} `json:"fields"`
-	} `json:"document"`
+// firestoreDocument is a word document as returned by the Firestore REST API.
+type firestoreDocument struct {
+	Name   string `json:"name"`
+	Fields struct {
+		Text struct {
+			StringValue string `json:"stringValue"`
+		} `json:"text"`
+		Hint struct {
+			StringValue string `json:"stringValue"`
+		} `json:"hint"`
+		Category struct {
+			StringValue string `json:"stringValue"`
+		} `json:"category"`
+	} `json:"fields"`
 }
 
-// getLanguageCode maps the provided language to the corresponding Firestore collection name.
-func getLanguageCode(lang string) (string) {
-	if lang == "uk" {
-		return "ua"
+// defaultFirestoreBaseURL is the production Firestore REST endpoint.
+const defaultFirestoreBaseURL = "https://firestore.googleapis.com/v1/"
+
+// FirestoreBaseURL returns the Firestore REST base URL, always ending in "/".
+// FIRESTORE_BASE_URL overrides it outright (e.g. a fake server in tests); otherwise
+// FIRESTORE_EMULATOR_HOST ("host:port") points it at a local Firestore emulator.
+func FirestoreBaseURL() string {
+	_ = godotenv.Load()
+
+	if baseURL := os.Getenv("FIRESTORE_BASE_URL"); baseURL != "" {
+		return strings.TrimSuffix(baseURL, "/") + "/"
+	}
+	if emulatorHost := os.Getenv("FIRESTORE_EMULATOR_HOST"); emulatorHost != "" {
+		return "http://" + emulatorHost + "/v1/"
 	}
-	return lang
+	return defaultFirestoreBaseURL
 }
 
 // getEnvVars loads and returns required Firebase environment variables.
@@ -47,10 +64,6 @@ func getEnvVars() (string, string, error) {
 	_ = godotenv.Load()
 
 	firebaseProjectID := os.Getenv("FIREBASE_PROJECT_ID")
-	if firebaseProjectID == "" {
-		// Fallback for environments where env vars are not easily set (like WASM/fyne serve)
-		firebaseProjectID = "hangman-go-f62b3"
-	}
 
 	firebaseAppID := os.Getenv("FIREBASE_APP_ID")
 	if firebaseAppID == "" {
@@ -67,121 +80,360 @@ func getEnvVars() (string, string, error) {
 	return firebaseProjectID, firebaseAppID, nil
 }
 
-// FetchRandomWord fetches a random word from Firestore based on the specified language.
-func FetchRandomWord(lang string) (*WordRecord, error) {
+// FirestoreSource is a WordSource backed by the Firestore REST API.
+//
+// Words are picked uniformly: the source keeps a cached index of every document name (and the
+// fields queries filter on) per language, draws one matching name uniformly at random and
+// fetches exactly that document.
+type FirestoreSource struct {
+	BaseURL   string
+	ProjectID string
+	AppID     string
+	client    *http.Client
+
+	// IndexTTL is how long a language's document index is reused before it is listed again.
+	IndexTTL time.Duration
+	// Retry controls how transient Firestore failures are retried.
+	Retry RetryPolicy
+	// Auth signs requests with a service account; nil means unauthenticated access.
+	Auth *TokenSource
+
+	breaker *circuitBreaker
+
+	mu      sync.Mutex
+	indexes map[string]*documentIndex
+}
+
+// documentIndex is the cached list of documents in one language collection.
+type documentIndex struct {
+	entries  []indexEntry
+	loadedAt time.Time
+	// valid is false until the first listing succeeds, and again once the index is invalidated.
+	valid bool
+	// loading is closed when the running listing finishes; nil while none is running.
+	loading chan struct{}
+	// err is the error of the last listing.
+	err error
+}
+
+// indexEntry is the name of an indexed document and the fields queries filter on.
+type indexEntry struct {
+	name     string
+	text     string
+	category string
+	traits   wordTraits
+}
+
+var (
+	_ WordSource     = (*FirestoreSource)(nil)
+	_ CategoryLister = (*FirestoreSource)(nil)
+)
+
+// firestoreListPageSize is the page size used when listing a collection to build its index.
+const firestoreListPageSize = 300
+
+// firestoreIndexTimeout bounds the listing of a collection. Listings run detached from the
+// request that started them, so they are not cut short by a single word fetch's deadline.
+const firestoreIndexTimeout = time.Minute
+
+// firestorePickAttempts is how many times a word is picked when the picked documents keep turning
+// out deleted or changed since the index was built.
+const firestorePickAttempts = 3
+
+// NewFirestoreSource creates a FirestoreSource using the Firebase environment variables.
+func NewFirestoreSource() (*FirestoreSource, error) {
 	firebaseProjectID, firebaseAppID, err := getEnvVars()
 	if err != nil {
 		return nil, err
 	}
+	indexTTL, err := durationFromEnv("FIRESTORE_INDEX_TTL", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := NewFirestoreAuthFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return &FirestoreSource{
+		BaseURL:   FirestoreBaseURL(),
+		ProjectID: firebaseProjectID,
+		AppID:     firebaseAppID,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		IndexTTL:  indexTTL,
+		Retry:     RetryPolicy{Attempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 2 * time.Second},
+		Auth:      auth,
+		breaker:   newCircuitBreaker(5, 30*time.Second),
+		indexes:   make(map[string]*documentIndex),
+	}, nil
+}
+
+// parentPath returns the Firestore path of the document holding the language collections.
+func (source *FirestoreSource) parentPath() string {
+	return fmt.Sprintf("projects/%s/databases/(default)/documents/artifacts/%s/public/data", source.ProjectID, source.AppID)
+}
+
+// FetchRandomWord fetches a uniformly random word matching the query from Firestore.
+func (source *FirestoreSource) FetchRandomWord(ctx context.Context, query WordQuery) (*WordRecord, error) {
+	firestoreLang := collectionFor(query.Language)
+
+	for attempt := 1; ; attempt++ {
+		entries, err := source.documentIndex(ctx, firestoreLang)
+		if err != nil {
+			return nil, err
+		}
+		var candidates []string
+		for _, entry := range entries {
+			if query.matches(entry.text, entry.category, entry.traits) {
+				candidates = append(candidates, entry.name)
+			}
+		}
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("%w in Firestore collection %q", ErrNoWords, firestoreLang)
+		}
+		pickedName := candidates[rand.IntN(len(candidates))]
+
+		doc, err := source.fetchDocument(ctx, pickedName)
+		if err != nil {
+			return nil, err
+		}
+		if doc != nil {
+			category := normalizeCategory(doc.Fields.Category.StringValue)
+			if query.matches(doc.Fields.Text.StringValue, category, measureWord(doc.Fields.Text.StringValue, firestoreLang)) {
+				return &WordRecord{
+					Text:     doc.Fields.Text.StringValue,
+					Hint:     doc.Fields.Hint.StringValue,
+					Language: query.Language,
+					Category: category,
+					Source:   "firestore",
+				}, nil
+			}
+		}
+
+		// The document was deleted or changed since the index was built: relist and pick again.
+		source.invalidateIndex(firestoreLang)
+		if attempt == firestorePickAttempts {
+			return nil, fmt.Errorf("document %s changed while picking a word from Firestore collection %q", pickedName, firestoreLang)
+		}
+	}
+}
 
-	firestoreLang := getLanguageCode(lang)
-
-	// Generate a random index
-	randomIndex := GenerateRandomIndex(20)
-
-	// Construct Firestore document paths
-	fullParentPath := fmt.Sprintf("projects/%s/databases/(default)/documents/artifacts/%s/public/data", firebaseProjectID, firebaseAppID)
-	jumpToPath := fmt.Sprintf("%s/%s/%s", fullParentPath, firestoreLang, randomIndex)
-	firebaseURL := fmt.Sprintf("https://firestore.googleapis.com/v1/%s:runQuery", fullParentPath)
-
-	// Build Firestore query
-	query := map[string]any{
-		"structuredQuery": map[string]any{
-			"from": []map[string]any{
-				{"collectionId": firestoreLang},
-			},
-			"orderBy": []map[string]any{
-				{
-					"field":     map[string]string{"fieldPath": "__name__"},
-					"direction": "ASCENDING",
-				},
-			},
-			"startAt": map[string]any{
-				"values": []map[string]any{
-					{"referenceValue": jumpToPath},
-				},
-			},
-			"limit": 1,
-		},
-	}
-
-	queryData, err := json.Marshal(query)
+// Categories counts the words per category in a language collection, using the document index.
+func (source *FirestoreSource) Categories(ctx context.Context, lang string) ([]CategoryCount, error) {
+	entries, err := source.documentIndex(ctx, collectionFor(lang))
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal Firestore query: %w", err)
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		counts[entry.category]++
 	}
+	return countCategories(counts), nil
+}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	response, err := client.Post(firebaseURL, "application/json", bytes.NewBuffer(queryData))
+// fetchDocument reads a document by its full name. It returns nil if the document doesn't exist.
+func (source *FirestoreSource) fetchDocument(ctx context.Context, documentName string) (*firestoreDocument, error) {
+	response, err := source.do(ctx, source.BaseURL+documentName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make HTTP request: %w", err)
+		return nil, err
 	}
 	defer response.Body.Close()
 
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
 	if response.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("Firestore API returned status: %s", response.Status)
 	}
 
-	var results FirestoreQueryResult
-	if err := json.NewDecoder(response.Body).Decode(&results); err != nil {
+	var doc firestoreDocument
+	if err := json.NewDecoder(response.Body).Decode(&doc); err != nil {
 		return nil, fmt.Errorf("failed to decode Firestore response: %w", err)
 	}
+	return &doc, nil
+}
 
-	if len(results) == 0 || results[0].Document.Fields.Text.StringValue == "" {
-		// Fallback to a predefined word if no result
-		return fetchFallbackWord(firebaseProjectID, firebaseAppID, firestoreLang, lang)
+// documentIndex returns the cached document index for a language. Concurrent callers share a
+// single listing of the collection. A stale index is served while it is listed again in the
+// background; callers only wait when there is no valid index yet.
+func (source *FirestoreSource) documentIndex(ctx context.Context, firestoreLang string) ([]indexEntry, error) {
+	source.mu.Lock()
+	index, exists := source.indexes[firestoreLang]
+	if !exists {
+		index = &documentIndex{}
+		source.indexes[firestoreLang] = index
+	}
+	if index.valid && time.Since(index.loadedAt) < source.IndexTTL {
+		source.mu.Unlock()
+		return index.entries, nil
+	}
+	if index.loading == nil {
+		index.loading = make(chan struct{})
+		go source.loadIndex(context.WithoutCancel(ctx), firestoreLang, index)
 	}
+	if index.valid {
+		entries := index.entries
+		source.mu.Unlock()
+		return entries, nil
+	}
+	loading := index.loading
+	source.mu.Unlock()
 
-	doc := results[0].Document
-	return &WordRecord{
-		Text:     doc.Fields.Text.StringValue,
-		Hint:     doc.Fields.Hint.StringValue,
-		Language: lang,
-	}, nil
+	select {
+	case <-loading:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	if index.err != nil {
+		return nil, index.err
+	}
+	return index.entries, nil
 }
 
-// fetchFallbackWord fetches a word from a predefined fallback location in Firestore.
-func fetchFallbackWord(firebaseProjectID, firebaseAppID, firestoreLang, originalLang string) (*WordRecord, error) {
-	fallbackURL := fmt.Sprintf(
-		"https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents/artifacts/%s/public/data/%s?pageSize=1",
-		firebaseProjectID, firebaseAppID, firestoreLang,
-	)
+// loadIndex lists a language collection into its index and wakes up the callers waiting for it.
+func (source *FirestoreSource) loadIndex(ctx context.Context, firestoreLang string, index *documentIndex) {
+	ctx, cancel := context.WithTimeout(ctx, firestoreIndexTimeout)
+	defer cancel()
+	entries, err := source.listDocuments(ctx, firestoreLang)
 
-	response, err := http.Get(fallbackURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make fallback HTTP request: %w", err)
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	// A failed listing keeps serving the previous index, if it is still valid.
+	if err == nil {
+		index.entries = entries
+		index.loadedAt = time.Now()
+		index.valid = true
 	}
-	defer response.Body.Close()
+	index.err = err
+	close(index.loading)
+	index.loading = nil
+}
 
-	var data struct {
-		Documents []struct {
-			Fields struct {
-				Text struct {
-					StringValue string `json:"stringValue"`
-				} `json:"text"`
-				Hint struct {
-					StringValue string `json:"stringValue"`
-				} `json:"hint"`
-			} `json:"fields"`
-		} `json:"documents"`
-	}
-
-	if err := json.NewDecoder(response.Body).Decode(&data); err != nil || len(data.Documents) == 0 {
-		return nil, fmt.Errorf("failed to decode fallback response or no documents found")
-	}
-	return &WordRecord{
-		Text:     data.Documents[0].Fields.Text.StringValue,
-		Hint:     data.Documents[0].Fields.Hint.StringValue,
-		Language: originalLang,
-	}, nil
+// invalidateIndex marks the cached document index for a language as out of date, so the next
+// caller waits for the collection to be listed again.
+func (source *FirestoreSource) invalidateIndex(firestoreLang string) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	if index, exists := source.indexes[firestoreLang]; exists {
+		index.valid = false
+	}
+}
+
+// listDocuments pages through a language collection and returns an index entry for each of its documents.
+func (source *FirestoreSource) listDocuments(ctx context.Context, firestoreLang string) ([]indexEntry, error) {
+	collectionURL := fmt.Sprintf("%s%s/%s", source.BaseURL, source.parentPath(), firestoreLang)
+
+	var entries []indexEntry
+	pageToken := ""
+	for {
+		params := url.Values{}
+		params.Set("pageSize", fmt.Sprint(firestoreListPageSize))
+		params["mask.fieldPaths"] = []string{"text", "category"}
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+
+		response, err := source.do(ctx, collectionURL+"?"+params.Encode())
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Documents     []firestoreDocument `json:"documents"`
+			NextPageToken string              `json:"nextPageToken"`
+		}
+		if response.StatusCode != http.StatusOK {
+			response.Body.Close()
+			return nil, fmt.Errorf("Firestore API returned status: %s", response.Status)
+		}
+		err = json.NewDecoder(response.Body).Decode(&page)
+		response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Firestore collection listing: %w", err)
+		}
+
+		for _, doc := range page.Documents {
+			entries = append(entries, indexEntry{
+				name:     doc.Name,
+				text:     doc.Fields.Text.StringValue,
+				category: normalizeCategory(doc.Fields.Category.StringValue),
+				traits:   measureWord(doc.Fields.Text.StringValue, firestoreLang),
+			})
+		}
+		if page.NextPageToken == "" {
+			return entries, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// do sends an authorized Firestore GET request, retrying network errors and transient
+// statuses with jittered backoff. Calls are rejected with ErrCircuitOpen while Firestore keeps failing.
+// The caller must close the response body.
+func (source *FirestoreSource) do(ctx context.Context, requestURL string) (*http.Response, error) {
+	if err := source.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= max(source.Retry.Attempts, 1); attempt++ {
+		if attempt > 1 {
+			if err := sleep(ctx, source.Retry.backoff(attempt-1)); err != nil {
+				source.breaker.release()
+				return nil, fmt.Errorf("%w (last error: %v)", err, lastErr)
+			}
+		}
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+		if err != nil {
+			source.breaker.release()
+			return nil, fmt.Errorf("failed to build HTTP request: %w", err)
+		}
+		if err := source.Auth.Authorize(request); err != nil {
+			lastErr = fmt.Errorf("failed to authorize Firestore request: %w", err)
+			continue
+		}
+
+		response, err := source.client.Do(request)
+		if err != nil {
+			if ctx.Err() != nil {
+				source.breaker.release()
+				return nil, fmt.Errorf("failed to make HTTP request: %w", ctx.Err())
+			}
+			lastErr = fmt.Errorf("failed to make HTTP request: %w", err)
+			continue
+		}
+		if response.StatusCode == http.StatusUnauthorized && source.Auth != nil {
+			// The token may have been revoked or expired early; fetch a fresh one and retry.
+			response.Body.Close()
+			source.Auth.Invalidate()
+			lastErr = fmt.Errorf("Firestore API returned status: %s", response.Status)
+			continue
+		}
+		if isTransientStatus(response.StatusCode) {
+			response.Body.Close()
+			lastErr = fmt.Errorf("Firestore API returned status: %s", response.Status)
+			continue
+		}
+
+		source.breaker.record(true)
+		return response, nil
+	}
+
+	source.breaker.record(false)
+	return nil, lastErr
 }
 
-// GenerateRandomIndex creates a random string of the given length using alphanumeric characters.
-func GenerateRandomIndex(length int) string {
-	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	b := make([]byte, length)
-	for i := range b {
-		b[i] = letters[r.Intn(len(letters))]
+// isTransientStatus reports whether a Firestore HTTP status is worth retrying.
+func isTransientStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
 	}
-	return string(b)
+	return false
 }
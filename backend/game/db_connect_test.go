@@ -0,0 +1,151 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hangman/backend/firestorefake"
+)
+
+// newFakeFirestoreSource starts an in-process Firestore fake and returns a FirestoreSource
+// pointed at it, along with the fake and the path of the English collection.
+func newFakeFirestoreSource(t *testing.T) (*FirestoreSource, *firestorefake.Server, string) {
+	t.Helper()
+
+	fake := firestorefake.New()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	t.Setenv("FIRESTORE_BASE_URL", server.URL+"/v1/")
+	t.Setenv("FIRESTORE_EMULATOR_HOST", "")
+	t.Setenv("FIREBASE_PROJECT_ID", "demo-hangman")
+	t.Setenv("FIREBASE_APP_ID", "go-hangman-v1")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+
+	source, err := NewFirestoreSource()
+	if err != nil {
+		t.Fatalf("NewFirestoreSource: %v", err)
+	}
+	collection := firestorefake.DocumentsRoot("demo-hangman") + "/artifacts/go-hangman-v1/public/data/en"
+	return source, fake, collection
+}
+
+func TestFirestoreFetchRandomWordIsUniform(t *testing.T) {
+	source, fake, collection := newFakeFirestoreSource(t)
+
+	// Document IDs are spread very unevenly, so a pick based on a random position in the ID space
+	// (rather than a uniform pick among the documents) would favour some words heavily.
+	words := map[string]string{"0000000001": "CAT", "0000000002": "DOG", "zzzzzzzzzz": "COMPUTER"}
+	for id, text := range words {
+		fake.AddDocument(collection, id, map[string]string{"text": text, "hint": "hint"})
+	}
+
+	const draws = 3000
+	counts := make(map[string]int)
+	for i := 0; i < draws; i++ {
+		word, err := source.FetchRandomWord(context.Background(), WordQuery{Language: "en"})
+		if err != nil {
+			t.Fatalf("FetchRandomWord: %v", err)
+		}
+		counts[word.Text]++
+	}
+
+	// Chi-square goodness of fit against the uniform distribution, with 2 degrees of freedom.
+	// 13.82 is the critical value at p = 0.001, so a correct implementation fails 1 run in 1000.
+	expected := float64(draws) / float64(len(words))
+	chiSquare := 0.0
+	for _, text := range words {
+		diff := float64(counts[text]) - expected
+		chiSquare += diff * diff / expected
+	}
+	if chiSquare > 13.82 {
+		t.Errorf("words are not drawn uniformly: counts %v, chi-square %.2f", counts, chiSquare)
+	}
+}
+
+func TestFirestoreFetchRandomWordSkipsDeletedDocuments(t *testing.T) {
+	source, fake, collection := newFakeFirestoreSource(t)
+	fake.AddDocument(collection, "a", map[string]string{"text": "CAT", "hint": "hint", "category": "animals"})
+	fake.AddDocument(collection, "b", map[string]string{"text": "COMPUTER", "hint": "hint", "category": "technology"})
+	fake.AddDocument(collection, "c", map[string]string{"text": "DOG", "hint": "hint", "category": "animals"})
+
+	query := WordQuery{Language: "en", Category: "animals", Exclude: []string{"CAT"}}
+	if _, err := source.FetchRandomWord(context.Background(), query); err != nil {
+		t.Fatalf("FetchRandomWord: %v", err)
+	}
+
+	// DOG is deleted after the index was built; the next document by name is COMPUTER, which
+	// doesn't match the query and must not be served.
+	fake.DeleteDocument(collection, "c")
+	word, err := source.FetchRandomWord(context.Background(), query)
+	if err == nil {
+		t.Fatalf("FetchRandomWord served %s (%s) for %+v", word.Text, word.Category, query)
+	}
+	if !errors.Is(err, ErrNoWords) {
+		t.Errorf("FetchRandomWord error = %v, want ErrNoWords", err)
+	}
+}
+
+func TestFirestoreDocumentIndexIsListedOnce(t *testing.T) {
+	source, fake, collection := newFakeFirestoreSource(t)
+	fake.AddDocument(collection, "a", map[string]string{"text": "CAT", "hint": "hint"})
+	fake.AddDocument(collection, "b", map[string]string{"text": "DOG", "hint": "hint"})
+
+	// Count the collection listings, and slow them down so that concurrent callers overlap.
+	var listings atomic.Int32
+	counting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/en") {
+			listings.Add(1)
+			time.Sleep(100 * time.Millisecond)
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(counting.Close)
+	source.BaseURL = counting.URL + "/v1/"
+
+	fetchConcurrently := func() {
+		var wg sync.WaitGroup
+		for range 9 {
+			wg.Go(func() {
+				if _, err := source.FetchRandomWord(context.Background(), WordQuery{Language: "en"}); err != nil {
+					t.Errorf("FetchRandomWord: %v", err)
+				}
+			})
+		}
+		wg.Wait()
+	}
+
+	fetchConcurrently()
+	if got := listings.Load(); got != 1 {
+		t.Errorf("cold index listed %d times, want 1", got)
+	}
+
+	// Once the index is stale, callers keep being served from it while a single listing refreshes it.
+	source.IndexTTL = time.Nanosecond
+	fetchConcurrently()
+	deadline := time.Now().Add(time.Second)
+	for listings.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := listings.Load(); got != 2 {
+		t.Errorf("stale index listed %d times in total, want 2", got)
+	}
+
+	// Let the background listing finish before the test returns.
+	for {
+		source.mu.Lock()
+		loading := source.indexes["en"].loading
+		source.mu.Unlock()
+		if loading == nil {
+			break
+		}
+		<-loading
+	}
+}
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"math"
+	"sort"
+	"unicode"
+)
+
+// DifficultyPreset is the set of rules a difficulty level ("Easy", "Normal", "Hard") implies.
+type DifficultyPreset struct {
+	Name               string
+	MaxAttempts        int
+	OpenLetterAttempts int
+	// SolvePenalty is the number of attempts a wrong whole-word guess costs. A penalty of
+	// MaxAttempts or more ends the game.
+	SolvePenalty int
+	// Scoring weighs the score of the games won at this difficulty.
+	Scoring ScoreWeights
+	// MinWordDifficulty and MaxWordDifficulty bound the WordDifficulty of the words drawn.
+	MinWordDifficulty float64
+	MaxWordDifficulty float64
+}
+
+// difficultyPresets holds the built-in presets. The word difficulty bands overlap a little so
+// that small word banks still have words for every level.
+var difficultyPresets = map[string]DifficultyPreset{
+	"Easy": {
+		Name: "Easy", MaxAttempts: 7, OpenLetterAttempts: 2, SolvePenalty: 1, MinWordDifficulty: 0, MaxWordDifficulty: 0.3,
+		Scoring: ScoreWeights{Win: 100, PerTryLeft: 10, PerRevealLeft: 15, NoHint: 20, Speed: 50, SpeedLimitSeconds: 300},
+	},
+	"Normal": {
+		Name: "Normal", MaxAttempts: 5, OpenLetterAttempts: 1, SolvePenalty: 2, MinWordDifficulty: 0.2, MaxWordDifficulty: 0.45,
+		Scoring: ScoreWeights{Win: 200, PerTryLeft: 20, PerRevealLeft: 30, NoHint: 40, Speed: 100, SpeedLimitSeconds: 240},
+	},
+	"Hard": {
+		Name: "Hard", MaxAttempts: 3, OpenLetterAttempts: 0, SolvePenalty: 3, MinWordDifficulty: 0.4, MaxWordDifficulty: 1,
+		Scoring: ScoreWeights{Win: 400, PerTryLeft: 40, NoHint: 80, Speed: 200, SpeedLimitSeconds: 180},
+	},
+}
+
+// LookupDifficulty returns the preset for a difficulty name, defaulting to Easy for unknown names.
+func LookupDifficulty(name string) DifficultyPreset {
+	if preset, exists := difficultyPresets[name]; exists {
+		return preset
+	}
+	return difficultyPresets["Easy"]
+}
+
+// DifficultyPresets returns every built-in preset, easiest first.
+func DifficultyPresets() []DifficultyPreset {
+	return []DifficultyPreset{difficultyPresets["Easy"], difficultyPresets["Normal"], difficultyPresets["Hard"]}
+}
+
+// NearestPresets returns the built-in presets ordered by how close their word difficulty band is
+// to the band of a preset, starting with the preset itself.
+func NearestPresets(preset DifficultyPreset) []DifficultyPreset {
+	center := (preset.MinWordDifficulty + preset.MaxWordDifficulty) / 2
+	presets := DifficultyPresets()
+	sort.SliceStable(presets, func(i, j int) bool {
+		distanceI := math.Abs((presets[i].MinWordDifficulty+presets[i].MaxWordDifficulty)/2 - center)
+		distanceJ := math.Abs((presets[j].MinWordDifficulty+presets[j].MaxWordDifficulty)/2 - center)
+		return distanceI < distanceJ
+	})
+	return presets
+}
+
+// wordTraits are the measurements of a word that word queries filter on.
+type wordTraits struct {
+	length     int
+	difficulty float64
+}
+
+// measureWord computes the traits of a word in a language.
+func measureWord(word, lang string) wordTraits {
+	return wordTraits{length: letterCount(word), difficulty: WordDifficulty(word, lang)}
+}
+
+// letterCount returns the number of letters in a word, ignoring spaces and punctuation.
+func letterCount(word string) int {
+	count := 0
+	for _, char := range word {
+		if unicode.IsLetter(char) {
+			count++
+		}
+	}
+	return count
+}
+
+// WordDifficulty rates a word from 0 (easiest) to 1 (hardest) for a language.
+// Longer words, words with more distinct letters and words using letters that are rare in the
+// language rate as harder.
+func WordDifficulty(word, lang string) float64 {
+	language, _ := LookupLanguage(lang)
+	frequencies := language.frequencies
+	maxFrequency := 0.0
+	for _, frequency := range frequencies {
+		maxFrequency = math.Max(maxFrequency, frequency)
+	}
+
+	length := 0
+	distinct := make(map[rune]bool)
+	for _, char := range word {
+		if unicode.IsLetter(char) {
+			length++
+			distinct[language.FoldLetter(char)] = true
+		}
+	}
+	if length == 0 {
+		return 0
+	}
+
+	// Rarity of a letter is 1 for letters unknown to the language and 0 for its most common letter.
+	totalRarity := 0.0
+	for letter := range distinct {
+		rarity := 1.0
+		if frequency, known := frequencies[letter]; known && maxFrequency > 0 {
+			rarity = 1 - frequency/maxFrequency
+		}
+		totalRarity += rarity
+	}
+	averageRarity := totalRarity / float64(len(distinct))
+
+	lengthScore := clamp01(float64(length-3) / 9)
+	distinctScore := clamp01(float64(len(distinct)-2) / 8)
+	rarityScore := clamp01((averageRarity - 0.5) / 0.5)
+	return 0.4*lengthScore + 0.3*distinctScore + 0.3*rarityScore
+}
+
+// clamp01 limits a value to the range [0, 1].
+func clamp01(value float64) float64 {
+	return math.Min(1, math.Max(0, value))
+}
@@ -0,0 +1,19 @@
+package game
+
+import "errors"
+
+// Errors returned (possibly wrapped) by the moves of a Game.
+var (
+	// ErrGameOver is returned for moves on a game that is won, lost or abandoned.
+	ErrGameOver = errors.New("game is over")
+	// ErrAlreadyGuessed is returned when a letter is guessed a second time.
+	ErrAlreadyGuessed = errors.New("letter already guessed")
+	// ErrInvalidLetter is returned for guesses that aren't a letter of the game's language.
+	ErrInvalidLetter = errors.New("invalid letter")
+	// ErrNoRevealsLeft is returned when a letter is opened with no open letter attempts left.
+	ErrNoRevealsLeft = errors.New("no open letter attempts left")
+	// ErrNoLettersToOpen is returned when a letter is opened but every letter is revealed already.
+	ErrNoLettersToOpen = errors.New("no letters to open")
+	// ErrUnsupportedLanguage is returned for a language code the registry doesn't know.
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+)
@@ -1,73 +1,267 @@
 package game
 
 import (
-	"strings"
-	"unicode"
+	"fmt"
+	"sort"
+	"time"
 )
 
 type Game struct {
-	TargetWord         string
-	Hint               string
+	TargetWord string
+	Hint       string
+	// GuessedLetters holds every letter that can't be guessed again: the guessed and opened letters,
+	// and in diacritic-tolerant games the variants a guessed base letter revealed.
 	GuessedLetters     map[rune]bool
 	IncorrectGuesses   int
-	CurrentWordState   []string
+	Mask               LetterMask
 	MaxAttempts        int
 	IsWordGuessed      bool
 	GetDisplayWord     string
 	Language           string
+	Difficulty         string
 	OpenLetterAttempts int
+	// DiacriticTolerant makes a guessed base letter also reveal its accented variants (e.g. "l" reveals "ł").
+	DiacriticTolerant bool
+	// SolvePenalty is the number of attempts a wrong whole-word guess costs.
+	SolvePenalty int
+	Status       Status
+	StartedAt    time.Time
+	setup        GameSetup
+	moves        []Move
 }
 
-func NewGame(targetWord, hint string, maxAttempts int, openLetterAttempts int, language string) *Game {
-	currentWordState := make([]string, 0)
-	for _, r := range targetWord {
-		if unicode.IsLetter(r) {
-			currentWordState = append(currentWordState, "_")
-		}
+// GameSetup is everything a game starts from. Replaying a game's moves on a new game with the
+// same setup rebuilds the game.
+type GameSetup struct {
+	TargetWord         string
+	Hint               string
+	MaxAttempts        int
+	OpenLetterAttempts int
+	Language           string
+	Difficulty         string
+	DiacriticTolerant  bool
+	SolvePenalty       int
+	Scoring            ScoreWeights
+	// StartedAt defaults to the time the game is created.
+	StartedAt time.Time
+}
+
+func NewGame(setup GameSetup) *Game {
+	wordLanguage, _ := LookupLanguage(setup.Language)
+	setup.TargetWord = NormalizeText(setup.TargetWord)
+	if setup.StartedAt.IsZero() {
+		setup.StartedAt = time.Now()
 	}
 
 	return &Game{
-		TargetWord:         targetWord,
-		Hint:               hint,
+		TargetWord:         setup.TargetWord,
+		Hint:               setup.Hint,
 		GuessedLetters:     make(map[rune]bool),
 		IncorrectGuesses:   0,
-		CurrentWordState:   currentWordState,
-		MaxAttempts:        maxAttempts,
-		Language:           language,
-		OpenLetterAttempts: openLetterAttempts,
+		Mask:               NewLetterMask(setup.TargetWord, wordLanguage),
+		MaxAttempts:        setup.MaxAttempts,
+		Language:           setup.Language,
+		Difficulty:         setup.Difficulty,
+		OpenLetterAttempts: setup.OpenLetterAttempts,
+		DiacriticTolerant:  setup.DiacriticTolerant,
+		SolvePenalty:       setup.SolvePenalty,
+		Status:             StatusInProgress,
+		StartedAt:          setup.StartedAt,
+		setup:              setup,
 	}
 }
 
-func (gameInstance *Game) MakeGuess(letter rune) bool {
-	letter = unicode.ToLower(letter)
+// Setup returns the setup the game started from.
+func (gameInstance *Game) Setup() GameSetup {
+	return gameInstance.setup
+}
 
+// MakeGuess guesses a letter and returns whether it is in the word. Guesses on a finished game,
+// of anything but a letter of the game's language and of letters already guessed are rejected
+// with ErrGameOver, ErrInvalidLetter and ErrAlreadyGuessed, and change nothing.
+func (gameInstance *Game) MakeGuess(letter rune) (bool, error) {
+	if gameInstance.Status.IsFinished() {
+		return false, ErrGameOver
+	}
+	language, _ := LookupLanguage(gameInstance.Language)
+	letter = language.FoldLetter(letter)
+
+	// Separators are always revealed, so guessing one is never a move.
+	if IsSeparator(letter) || !language.IsLetter(letter) {
+		return false, fmt.Errorf("%w: %q", ErrInvalidLetter, letter)
+	}
 	if gameInstance.GuessedLetters[letter] {
-		return false
+		return false, fmt.Errorf("%w: %q", ErrAlreadyGuessed, letter)
 	}
-	gameInstance.GuessedLetters[letter] = true
 
+	letters := []rune{letter}
+	if gameInstance.DiacriticTolerant {
+		letters = language.Variants(letter)
+	}
 	correctGuess := false
-	targetRunes := []rune(gameInstance.TargetWord)
-	for i, char := range targetRunes {
-		if unicode.ToLower(char) == letter {
-			gameInstance.CurrentWordState[i] = string(char)
+	for _, variant := range letters {
+		// Variants revealed through their base letter count as guessed, so guessing them again is free.
+		gameInstance.GuessedLetters[variant] = true
+		if gameInstance.Mask.RevealLetter(variant) > 0 {
 			correctGuess = true
 		}
 	}
 	if !correctGuess {
 		gameInstance.IncorrectGuesses++
 	}
-	return correctGuess
+	gameInstance.recordMove(MoveGuess, string(letter), correctGuess)
+	gameInstance.updateStatus()
+	return correctGuess, nil
+}
+
+// OpenLetter reveals every occurrence of the first hidden letter, at the cost of one open letter
+// attempt and one try, and returns the letter.
+func (gameInstance *Game) OpenLetter() (rune, error) {
+	if gameInstance.Status.IsFinished() {
+		return 0, ErrGameOver
+	}
+	if gameInstance.OpenLetterAttempts <= 0 {
+		return 0, ErrNoRevealsLeft
+	}
+	letter, ok := gameInstance.Mask.FirstHidden()
+	if !ok {
+		return 0, ErrNoLettersToOpen
+	}
+
+	language, _ := LookupLanguage(gameInstance.Language)
+	gameInstance.Mask.RevealLetter(letter)
+	gameInstance.GuessedLetters[language.FoldLetter(letter)] = true
+	gameInstance.OpenLetterAttempts--
+	gameInstance.IncorrectGuesses++
+	gameInstance.recordMove(MoveOpenLetter, string(letter), true)
+	gameInstance.updateStatus()
+	return letter, nil
+}
+
+// Solve checks a whole-word attempt. Case and separators (spaces, hyphens, ...) don't matter, and
+// in diacritic-tolerant games neither do accents. A correct attempt reveals the word and wins the
+// game; a wrong one costs SolvePenalty attempts. Attempts on a finished game return ErrGameOver.
+func (gameInstance *Game) Solve(attempt string) (bool, error) {
+	if gameInstance.Status.IsFinished() {
+		return false, ErrGameOver
+	}
+	language, _ := LookupLanguage(gameInstance.Language)
+	sameLetter := func(a, b rune) bool {
+		if gameInstance.DiacriticTolerant {
+			return language.BaseLetter(a) == language.BaseLetter(b)
+		}
+		return a == b
+	}
+
+	attempt = NormalizeText(attempt)
+	var attemptLetters []rune
+	for _, char := range attempt {
+		if !IsSeparator(char) {
+			attemptLetters = append(attemptLetters, char)
+		}
+	}
+	attemptLetters = language.FoldWord(attemptLetters)
+	targetLetters := language.FoldWord(gameInstance.Mask.Letters())
+
+	correct := len(attemptLetters) == len(targetLetters)
+	for i := 0; correct && i < len(targetLetters); i++ {
+		correct = sameLetter(attemptLetters[i], targetLetters[i])
+	}
+
+	if correct {
+		gameInstance.Mask.RevealAll()
+	} else {
+		gameInstance.IncorrectGuesses = min(gameInstance.IncorrectGuesses+gameInstance.SolvePenalty, gameInstance.MaxAttempts)
+	}
+	gameInstance.recordMove(MoveSolve, attempt, correct)
+	gameInstance.updateStatus()
+	return correct, nil
+}
+
+// ViewHint returns the hint of an in-progress game.
+func (gameInstance *Game) ViewHint() (string, error) {
+	if gameInstance.Status.IsFinished() {
+		return "", ErrGameOver
+	}
+	gameInstance.recordMove(MoveHint, "", true)
+	return gameInstance.Hint, nil
+}
+
+// WordLength returns the number of letters of the word, not counting separators.
+func (gameInstance *Game) WordLength() int {
+	return len(gameInstance.Mask.Letters())
+}
+
+// CorrectLetters returns the used letters whose guess or reveal was correct, sorted. In
+// diacritic-tolerant games, the accented variants a correct base letter guess used up are included.
+func (gameInstance *Game) CorrectLetters() []string {
+	return gameInstance.guessedLetters(true)
+}
+
+// WrongLetters returns the used letters whose guess was wrong, sorted.
+func (gameInstance *Game) WrongLetters() []string {
+	return gameInstance.guessedLetters(false)
+}
+
+// guessedLetters returns the letters of GuessedLetters that were used up by a guess or reveal
+// with the given outcome, sorted.
+func (gameInstance *Game) guessedLetters(correct bool) []string {
+	language, _ := LookupLanguage(gameInstance.Language)
+	outcomes := make(map[rune]bool)
+	for _, move := range gameInstance.moves {
+		if move.Kind != MoveGuess && move.Kind != MoveOpenLetter {
+			continue
+		}
+		letter := language.FoldLetter([]rune(move.Input)[0])
+		used := []rune{letter}
+		if move.Kind == MoveGuess && gameInstance.DiacriticTolerant {
+			used = language.Variants(letter)
+		}
+		for _, usedLetter := range used {
+			if _, seen := outcomes[usedLetter]; !seen {
+				outcomes[usedLetter] = move.Correct
+			}
+		}
+	}
+
+	letters := []string{}
+	for letter := range gameInstance.GuessedLetters {
+		if outcomes[letter] == correct {
+			letters = append(letters, string(letter))
+		}
+	}
+	sort.Strings(letters)
+	return letters
+}
+
+// HintViewed reports whether the player looked at the hint.
+func (gameInstance *Game) HintViewed() bool {
+	for _, move := range gameInstance.moves {
+		if move.Kind == MoveHint {
+			return true
+		}
+	}
+	return false
+}
+
+// FinishedAt returns when the game was won, lost or abandoned: the time of its last move.
+func (gameInstance *Game) FinishedAt() (time.Time, bool) {
+	if !gameInstance.Status.IsFinished() || len(gameInstance.moves) == 0 {
+		return time.Time{}, false
+	}
+	return gameInstance.moves[len(gameInstance.moves)-1].At, true
 }
 
-func IsWordGuessed(guessed []string, word string) bool {
-	return strings.Join(guessed, "") == word
+// IsWon reports whether the player revealed the whole word.
+func (gameInstance *Game) IsWon() bool {
+	return gameInstance.Status == StatusWon
 }
 
+// IsGameOver reports whether the game is finished: won, lost or abandoned.
 func (gameInstance *Game) IsGameOver() bool {
-	return gameInstance.IncorrectGuesses >= gameInstance.MaxAttempts || IsWordGuessed(gameInstance.CurrentWordState, gameInstance.TargetWord)
+	return gameInstance.Status.IsFinished()
 }
 
 func GetDisplayWord(gameInstance *Game) string {
-	return strings.Join(gameInstance.CurrentWordState, " ")
+	return RenderWord(gameInstance.Mask.Cells())
 }
@@ -0,0 +1,225 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Language describes a language words and guesses can be in.
+type Language struct {
+	Code    string   `json:"code"`
+	Aliases []string `json:"aliases,omitempty"`
+	Name    string   `json:"name"`
+	// Alphabet and Vowels list the lowercase letters of the language.
+	Alphabet string `json:"alphabet"`
+	Vowels   string `json:"vowels"`
+	// Diacritics maps a base letter to the accented letters it also reveals in diacritic-tolerant games.
+	Diacritics map[string]string `json:"diacritics,omitempty"`
+	// Transliteration maps Latin input, including multi-letter sequences such as "sh", to a letter of
+	// a non-Latin alphabet, so the language can be played from a Latin keyboard.
+	Transliteration map[string]string `json:"transliteration,omitempty"`
+	// Collection is the word collection (Firestore collection, word bank key) of the language.
+	Collection string `json:"-"`
+	// caseMapping holds locale-specific case rules, e.g. unicode.TurkishCase so that "I" folds to
+	// "ı" and "İ" to "i"; nil means the default Unicode rules, which suit en, pl and uk.
+	caseMapping unicode.SpecialCase
+	// wordFolds maps a letter to the letters it is spelled as when whole words are compared,
+	// e.g. "ß" to "ss" in German, so that solving "STRASSE" matches "STRAßE".
+	wordFolds map[rune]string
+	// frequencies holds approximate letter frequencies (percent), used to rate word difficulty.
+	frequencies map[rune]float64
+}
+
+// languages is the registry of supported languages, in display order.
+var languages = []Language{
+	{
+		Code:       "en",
+		Name:       "English",
+		Alphabet:   "abcdefghijklmnopqrstuvwxyz",
+		Vowels:     "aeiou",
+		Collection: "en",
+		frequencies: map[rune]float64{
+			'e': 12.7, 't': 9.1, 'a': 8.2, 'o': 7.5, 'i': 7.0, 'n': 6.7, 's': 6.3, 'h': 6.1, 'r': 6.0,
+			'd': 4.3, 'l': 4.0, 'c': 2.8, 'u': 2.8, 'm': 2.4, 'w': 2.4, 'f': 2.2, 'g': 2.0, 'y': 2.0,
+			'p': 1.9, 'b': 1.5, 'v': 1.0, 'k': 0.8, 'j': 0.15, 'x': 0.15, 'q': 0.1, 'z': 0.07,
+		},
+	},
+	{
+		Code: "pl",
+		Name: "Polski",
+		// q, v and x aren't native Polish letters but do occur in loanwords.
+		Alphabet: "aąbcćdeęfghijklłmnńoópqrsśtuvwxyzźż",
+		Vowels:   "aąeęioóuy",
+		Diacritics: map[string]string{
+			"a": "ą", "c": "ć", "e": "ę", "l": "ł", "n": "ń", "o": "ó", "s": "ś", "z": "źż",
+		},
+		Collection: "pl",
+		frequencies: map[rune]float64{
+			'a': 8.9, 'i': 8.2, 'o': 7.8, 'e': 7.7, 'z': 5.6, 'n': 5.5, 'r': 4.7, 'w': 4.7, 's': 4.3,
+			't': 4.0, 'c': 4.0, 'y': 3.8, 'k': 3.5, 'd': 3.3, 'p': 3.1, 'm': 2.8, 'u': 2.5, 'j': 2.3,
+			'l': 2.1, 'ł': 1.8, 'b': 1.5, 'g': 1.4, 'ę': 1.1, 'h': 1.1, 'ą': 1.0, 'ó': 0.9, 'ż': 0.8,
+			'ś': 0.7, 'ć': 0.4, 'f': 0.3, 'ń': 0.2, 'ź': 0.06,
+		},
+	},
+	{
+		Code:       "uk",
+		Aliases:    []string{"ua"},
+		Name:       "Українська",
+		Alphabet:   "абвгґдеєжзиіїйклмнопрстуфхцчшщьюя",
+		Vowels:     "аеєиіїоуюя",
+		Diacritics: map[string]string{"г": "ґ", "і": "ї"},
+		// Ukrainian national romanization. Where it is ambiguous ("i" is і, ї and й; "y" is и and й)
+		// the first reading wins and the other letters get their word-initial spelling, "j" for й.
+		// The romanization drops the soft sign, so ь can't be typed in Latin.
+		Transliteration: map[string]string{
+			"a": "а", "b": "б", "v": "в", "h": "г", "g": "ґ", "d": "д", "e": "е", "ie": "є", "ye": "є",
+			"zh": "ж", "z": "з", "y": "и", "i": "і", "yi": "ї", "j": "й", "k": "к", "l": "л", "m": "м",
+			"n": "н", "o": "о", "p": "п", "r": "р", "s": "с", "t": "т", "u": "у", "f": "ф", "kh": "х",
+			"ts": "ц", "ch": "ч", "sh": "ш", "shch": "щ", "iu": "ю", "yu": "ю", "ia": "я",
+			"ya": "я",
+		},
+		Collection: "ua",
+		frequencies: map[rune]float64{
+			'о': 9.3, 'а': 8.0, 'н': 6.5, 'и': 6.1, 'і': 5.9, 'т': 5.3, 'в': 5.2, 'р': 4.9, 'е': 4.8,
+			'с': 4.4, 'к': 4.0, 'л': 3.8, 'у': 3.7, 'д': 3.3, 'м': 3.2, 'п': 2.9, 'я': 2.1, 'з': 2.0,
+			'ь': 1.8, 'г': 1.6, 'б': 1.6, 'ч': 1.4, 'х': 1.2, 'ж': 1.0, 'й': 1.0, 'ц': 1.0, 'ю': 0.8,
+			'ї': 0.8, 'ш': 0.8, 'щ': 0.5, 'є': 0.4, 'ф': 0.3, 'ґ': 0.01,
+		},
+	},
+}
+
+// letterVariants maps typographic variants to the character words are stored with, e.g. the
+// Ukrainian modifier-letter apostrophe and right single quote to a plain apostrophe.
+var letterVariants = map[rune]rune{
+	'\u02BC': '\'',
+	'\u2019': '\'',
+	'\u02B9': '\'',
+}
+
+// Languages returns every supported language.
+func Languages() []Language {
+	return append([]Language(nil), languages...)
+}
+
+// LookupLanguage finds a language by its code or one of its aliases, ignoring case.
+func LookupLanguage(code string) (Language, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	for _, language := range languages {
+		if language.Code == code {
+			return language, true
+		}
+		for _, alias := range language.Aliases {
+			if alias == code {
+				return language, true
+			}
+		}
+	}
+	return Language{}, false
+}
+
+// IsLetter reports whether a rune is a letter of the language's alphabet, ignoring case. Without
+// an alphabet (an unknown language) any letter is accepted.
+func (language Language) IsLetter(char rune) bool {
+	if language.Alphabet == "" {
+		return !IsSeparator(char)
+	}
+	return strings.ContainsRune(language.Alphabet, language.FoldLetter(char))
+}
+
+// ParseLetter reads a guess as typed by a player: a single letter of the alphabet, or Latin input
+// the language transliterates (e.g. "sh" for "ш" in Ukrainian). It returns the folded letter and
+// whether the input was transliterated.
+func (language Language) ParseLetter(input string) (rune, bool, error) {
+	// Normalize first, so a letter typed as a base letter plus a combining accent counts as one letter.
+	input = NormalizeText(input)
+	runes := []rune(input)
+	if len(runes) == 1 && language.IsLetter(runes[0]) {
+		return language.FoldLetter(runes[0]), false, nil
+	}
+	if letter, ok := language.Transliterate(input); ok {
+		return letter, true, nil
+	}
+	if len(runes) != 1 || IsSeparator(runes[0]) {
+		return 0, false, fmt.Errorf("%w: guess must be a single letter", ErrInvalidLetter)
+	}
+	return 0, false, fmt.Errorf("%w for %s", ErrInvalidLetter, language.Name)
+}
+
+// IsVowel reports whether a rune is a vowel of the language, ignoring case.
+func (language Language) IsVowel(char rune) bool {
+	return strings.ContainsRune(language.Vowels, language.FoldLetter(char))
+}
+
+// Variants returns a folded letter together with the accented letters it stands for in
+// diacritic-tolerant games.
+func (language Language) Variants(letter rune) []rune {
+	return append([]rune{letter}, []rune(language.Diacritics[string(letter)])...)
+}
+
+// Transliterate maps Latin input such as "sh" to the letter of the language it stands for.
+// It returns false if the language has no transliteration for the input.
+func (language Language) Transliterate(input string) (rune, bool) {
+	letter, ok := language.Transliteration[strings.Map(language.FoldLetter, input)]
+	if !ok {
+		return 0, false
+	}
+	return []rune(letter)[0], true
+}
+
+// BaseLetter maps a folded accented letter to the base letter that stands for it in
+// diacritic-tolerant games (e.g. "ł" to "l"). Other letters map to themselves.
+func (language Language) BaseLetter(letter rune) rune {
+	for base, variants := range language.Diacritics {
+		if strings.ContainsRune(variants, letter) {
+			return []rune(base)[0]
+		}
+	}
+	return letter
+}
+
+// FoldLetter maps a letter to the form letters are compared in: its lowercase under the
+// language's case rules, with typographic variants unified.
+func (language Language) FoldLetter(char rune) rune {
+	if variant, ok := letterVariants[char]; ok {
+		char = variant
+	}
+	return language.caseMapping.ToLower(char)
+}
+
+// FoldWord maps the letters of a word to the form whole words are compared in: every letter
+// folded, and letters with a multi-letter spelling (such as German "ß") spelled out.
+func (language Language) FoldWord(letters []rune) []rune {
+	folded := make([]rune, 0, len(letters))
+	for _, char := range letters {
+		char = language.FoldLetter(char)
+		if spelling, ok := language.wordFolds[char]; ok {
+			folded = append(folded, []rune(spelling)...)
+			continue
+		}
+		folded = append(folded, char)
+	}
+	return folded
+}
+
+// NormalizeText puts words and guesses in a canonical form: NFC, so a decomposed "e" + combining
+// accent is a single letter, with typographic variants unified. Case is kept for display.
+func NormalizeText(text string) string {
+	return strings.Map(func(char rune) rune {
+		if variant, ok := letterVariants[char]; ok {
+			return variant
+		}
+		return char
+	}, norm.NFC.String(text))
+}
+
+// collectionFor maps a language code or alias to its word collection. Unknown languages map to
+// themselves, so word banks may carry languages the registry doesn't know.
+func collectionFor(code string) string {
+	if language, ok := LookupLanguage(code); ok {
+		return language.Collection
+	}
+	return code
+}
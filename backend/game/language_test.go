@@ -0,0 +1,43 @@
+package game
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestLanguageFoldingHooks(t *testing.T) {
+	turkish := Language{Code: "tr", Alphabet: "abcçdefgğhıijklmnoöprsştuüvyz", caseMapping: unicode.TurkishCase}
+	german := Language{Code: "de", Alphabet: "abcdefghijklmnopqrstuvwxyzäöüß", wordFolds: map[rune]string{'ß': "ss"}}
+
+	for _, test := range []struct {
+		language Language
+		letter   rune
+		want     rune
+	}{
+		{turkish, 'I', 'ı'},
+		{turkish, 'İ', 'i'},
+		{german, 'I', 'i'},
+		{german, 'ẞ', 'ß'},
+	} {
+		if got := test.language.FoldLetter(test.letter); got != test.want {
+			t.Errorf("%s FoldLetter(%q) = %q, want %q", test.language.Code, test.letter, got, test.want)
+		}
+	}
+
+	// Dotless I is its own letter in Turkish: "i" must not reveal it.
+	mask := NewLetterMask("ISTANBUL", turkish)
+	if revealed := mask.RevealLetter('i'); revealed != 0 {
+		t.Errorf("RevealLetter('i') on ISTANBUL revealed %d positions, want 0", revealed)
+	}
+	if revealed := mask.RevealLetter('ı'); revealed != 1 {
+		t.Errorf("RevealLetter('ı') on ISTANBUL revealed %d positions, want 1", revealed)
+	}
+
+	// "ß" may be spelled out as "ss" when whole words are compared.
+	target := string(german.FoldWord(NewLetterMask("STRAßE", german).Letters()))
+	for _, attempt := range []string{"straße", "STRASSE", "Strasse"} {
+		if got := string(german.FoldWord([]rune(attempt))); got != target {
+			t.Errorf("FoldWord(%q) = %q, want %q", attempt, got, target)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package game
+
+// LetterMask tracks which letters of a puzzle are revealed. It is indexed by rune position in
+// the target word, so multi-byte letters (Cyrillic, Polish diacritics) and separators each take
+// exactly one position. Separators, and letters outside the language's alphabet (which can't be
+// guessed, such as the "É" of an English "CAFÉ"), start out revealed. Letters are matched in the folded form of
+// the word's language, so case and typographic variants don't matter.
+type LetterMask struct {
+	language Language
+	runes    []rune
+	folded   []rune
+	revealed []bool
+}
+
+// NewLetterMask creates a mask for a target word in a language with every guessable letter
+// hidden. The word is normalized (see NormalizeText) first.
+func NewLetterMask(targetWord string, language Language) LetterMask {
+	runes := []rune(NormalizeText(targetWord))
+	folded := make([]rune, len(runes))
+	revealed := make([]bool, len(runes))
+	for i, char := range runes {
+		folded[i] = language.FoldLetter(char)
+		revealed[i] = IsSeparator(char) || !language.IsLetter(char)
+	}
+	return LetterMask{language: language, runes: runes, folded: folded, revealed: revealed}
+}
+
+// RevealLetter reveals every hidden occurrence of a letter, ignoring case, and returns how many
+// positions it revealed.
+func (mask *LetterMask) RevealLetter(letter rune) int {
+	letter = mask.language.FoldLetter(letter)
+	count := 0
+	for i, folded := range mask.folded {
+		if !mask.revealed[i] && folded == letter {
+			mask.revealed[i] = true
+			count++
+		}
+	}
+	return count
+}
+
+// RevealAll reveals every position.
+func (mask *LetterMask) RevealAll() {
+	for i := range mask.revealed {
+		mask.revealed[i] = true
+	}
+}
+
+// Letters returns the folded letters of the word, without separators.
+func (mask LetterMask) Letters() []rune {
+	letters := make([]rune, 0, len(mask.folded))
+	for i, folded := range mask.folded {
+		if !IsSeparator(mask.runes[i]) {
+			letters = append(letters, folded)
+		}
+	}
+	return letters
+}
+
+// FirstHidden returns the first letter that is still hidden.
+func (mask LetterMask) FirstHidden() (rune, bool) {
+	for i, char := range mask.runes {
+		if !mask.revealed[i] {
+			return char, true
+		}
+	}
+	return 0, false
+}
+
+// IsComplete reports whether every position is revealed.
+func (mask LetterMask) IsComplete() bool {
+	for _, revealed := range mask.revealed {
+		if !revealed {
+			return false
+		}
+	}
+	return true
+}
+
+// Cells returns one display cell per position: the rune if revealed, "_" otherwise.
+func (mask LetterMask) Cells() []string {
+	cells := make([]string, len(mask.runes))
+	for i, char := range mask.runes {
+		if mask.revealed[i] {
+			cells[i] = string(char)
+		} else {
+			cells[i] = hiddenCell
+		}
+	}
+	return cells
+}
@@ -0,0 +1,119 @@
+package game
+
+import (
+	"math/rand/v2"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+// sampleWords are real words and phrases with the separators puzzles can contain.
+var sampleWords = map[string][]string{
+	"uk": {"ПАМ'ЯТЬ", "М'ЯЧ", "ЇЖАК", "ҐАНОК", "ЧОРНЕ МОРЕ", "ЖОВТО-БЛАКИТНИЙ", "памʼять", "Щ"},
+	"pl": {"ŻÓŁW", "ŹDŹBŁO", "KSIĘŻYC", "BIAŁO-CZERWONY", "ŁÓDŹ PODWODNA", "ZAŻÓŁĆ GĘŚLĄ JAŹŃ", "ósma"},
+}
+
+// propertyWords returns the sample words of a language plus random words over its alphabet, in
+// mixed case, with apostrophes, spaces and hyphens mixed in.
+func propertyWords(t *testing.T, code string) (Language, []string) {
+	t.Helper()
+	language, ok := LookupLanguage(code)
+	if !ok {
+		t.Fatalf("language %q is not registered", code)
+	}
+
+	random := rand.New(rand.NewPCG(1, uint64(len(code))))
+	alphabet := []rune(language.Alphabet)
+	separators := []rune("' -")
+	words := append([]string(nil), sampleWords[code]...)
+	for range 500 {
+		runes := make([]rune, 1+random.IntN(15))
+		for i := range runes {
+			letter := alphabet[random.IntN(len(alphabet))]
+			switch {
+			case i > 0 && random.IntN(6) == 0:
+				runes[i] = separators[random.IntN(len(separators))]
+			case random.IntN(2) == 0:
+				runes[i] = unicode.ToUpper(letter)
+			default:
+				runes[i] = letter
+			}
+		}
+		words = append(words, string(runes))
+	}
+	return language, words
+}
+
+func TestLetterMaskProperties(t *testing.T) {
+	for _, code := range []string{"uk", "pl"} {
+		language, words := propertyWords(t, code)
+		for _, word := range words {
+			mask := NewLetterMask(word, language)
+
+			if cells, runes := len(mask.Cells()), utf8.RuneCountInString(NormalizeText(word)); cells != runes {
+				t.Errorf("%s %q: %d cells for %d runes", code, word, cells, runes)
+			}
+
+			for _, letter := range mask.Letters() {
+				if hidden, ok := mask.FirstHidden(); ok && IsSeparator(hidden) {
+					t.Errorf("%s %q: FirstHidden returned separator %q", code, word, hidden)
+				}
+				mask.RevealLetter(letter)
+			}
+			if !mask.IsComplete() {
+				t.Errorf("%s %q: not complete after revealing every letter: %v", code, word, mask.Cells())
+			}
+			if hidden, ok := mask.FirstHidden(); ok {
+				t.Errorf("%s %q: FirstHidden returned %q on a complete mask", code, word, hidden)
+			}
+		}
+	}
+}
+
+func TestGameMovesNeverPanic(t *testing.T) {
+	// Guesses include letters of every registered alphabet, separators, digits and symbols.
+	var guesses []rune
+	for _, language := range Languages() {
+		guesses = append(guesses, []rune(language.Alphabet)...)
+	}
+	guesses = append(guesses, []rune("' -ʼ’7😀ĄŁЇ")...)
+
+	for _, code := range []string{"uk", "pl"} {
+		_, words := propertyWords(t, code)
+		random := rand.New(rand.NewPCG(2, uint64(len(code))))
+		for _, word := range words {
+			gameInstance := NewGame(GameSetup{
+				TargetWord:         word,
+				MaxAttempts:        1 + random.IntN(10),
+				OpenLetterAttempts: random.IntN(3),
+				Language:           code,
+				DiacriticTolerant:  random.IntN(2) == 0,
+			})
+			for range 40 {
+				if random.IntN(5) == 0 {
+					gameInstance.OpenLetter()
+				} else {
+					gameInstance.MakeGuess(guesses[random.IntN(len(guesses))])
+				}
+			}
+			if tries := gameInstance.MaxAttempts - gameInstance.IncorrectGuesses; tries < 0 {
+				t.Errorf("%s %q: %d tries left", code, word, tries)
+			}
+		}
+	}
+}
+
+func TestLetterMaskRevealsLettersOutsideAlphabet(t *testing.T) {
+	gameInstance := NewGame(GameSetup{TargetWord: "CAFÉ", MaxAttempts: 5, Language: "en"})
+	if got, want := GetDisplayWord(gameInstance), "_ _ _ É"; got != want {
+		t.Errorf("display word = %q, want %q", got, want)
+	}
+	for _, letter := range "caf" {
+		if _, err := gameInstance.MakeGuess(letter); err != nil {
+			t.Fatalf("MakeGuess(%q): %v", letter, err)
+		}
+	}
+	if !gameInstance.IsWon() {
+		t.Errorf("guessing every letter of the alphabet in CAFÉ didn't win, status %s", gameInstance.Status)
+	}
+}
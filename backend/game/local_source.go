@@ -0,0 +1,118 @@
+package game
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+)
+
+// embeddedWordBank is the word bank bundled into the binary, in the seeder's words.json format.
+//
+//go:embed words.json
+var embeddedWordBank []byte
+
+// WordEntry represents a single word and its hint, as stored in words.json.
+type WordEntry struct {
+	Text     string `json:"text"`
+	Hint     string `json:"hint"`
+	Category string `json:"category,omitempty"`
+}
+
+// ParseWordBank decodes a words.json document mapping language codes to word entries.
+func ParseWordBank(data []byte) (map[string][]WordEntry, error) {
+	var wordBank map[string][]WordEntry
+	if err := json.Unmarshal(data, &wordBank); err != nil {
+		return nil, fmt.Errorf("failed to parse word bank: %w", err)
+	}
+	return wordBank, nil
+}
+
+// LocalSource is a WordSource that serves words from an in-memory word bank.
+type LocalSource struct {
+	name   string
+	words  map[string][]WordRecord
+	traits map[string][]wordTraits // traits[lang][i] measures words[lang][i]
+}
+
+var (
+	_ WordSource     = (*LocalSource)(nil)
+	_ CategoryLister = (*LocalSource)(nil)
+)
+
+// NewLocalSource creates a LocalSource from a parsed word bank.
+// name is reported as the Source of every word it serves.
+func NewLocalSource(name string, wordBank map[string][]WordEntry) *LocalSource {
+	words := make(map[string][]WordRecord, len(wordBank))
+	traits := make(map[string][]wordTraits, len(wordBank))
+	for lang, entries := range wordBank {
+		for _, entry := range entries {
+			text := normalizeWordKey(entry.Text)
+			if text == "" {
+				continue
+			}
+			words[lang] = append(words[lang], WordRecord{
+				Text:     text,
+				Hint:     entry.Hint,
+				Language: lang,
+				Category: normalizeCategory(entry.Category),
+				Source:   name,
+			})
+			traits[lang] = append(traits[lang], measureWord(text, lang))
+		}
+	}
+	return &LocalSource{name: name, words: words, traits: traits}
+}
+
+// NewEmbeddedSource creates a LocalSource from the word bank bundled into the binary.
+func NewEmbeddedSource() (*LocalSource, error) {
+	wordBank, err := ParseWordBank(embeddedWordBank)
+	if err != nil {
+		return nil, err
+	}
+	return NewLocalSource("embedded", wordBank), nil
+}
+
+// NewFileSource creates a LocalSource from a words.json file on disk.
+func NewFileSource(path string) (*LocalSource, error) {
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read word bank %s: %w", path, err)
+	}
+	wordBank, err := ParseWordBank(fileData)
+	if err != nil {
+		return nil, err
+	}
+	return NewLocalSource("local", wordBank), nil
+}
+
+// FetchRandomWord picks a random word matching the query from the local word bank.
+func (source *LocalSource) FetchRandomWord(ctx context.Context, query WordQuery) (*WordRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	lang := collectionFor(query.Language)
+	var candidates []WordRecord
+	for i, word := range source.words[lang] {
+		if query.matches(word.Text, word.Category, source.traits[lang][i]) {
+			candidates = append(candidates, word)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w for language %q", ErrNoWords, query.Language)
+	}
+	word := candidates[rand.IntN(len(candidates))]
+	word.Language = query.Language
+	return &word, nil
+}
+
+// Categories counts the words per category in the local word bank.
+func (source *LocalSource) Categories(ctx context.Context, lang string) ([]CategoryCount, error) {
+	counts := make(map[string]int)
+	for _, word := range source.words[collectionFor(lang)] {
+		counts[word.Category]++
+	}
+	return countCategories(counts), nil
+}
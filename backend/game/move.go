@@ -0,0 +1,97 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MoveKind is the kind of a move a player made.
+type MoveKind string
+
+const (
+	MoveGuess      MoveKind = "guess"
+	MoveOpenLetter MoveKind = "open_letter"
+	MoveHint       MoveKind = "hint"
+	MoveSolve      MoveKind = "solve"
+	MoveAbandon    MoveKind = "abandon"
+)
+
+// Move is one entry of a game's move log.
+type Move struct {
+	Kind MoveKind `json:"kind"`
+	// Input is the guessed or opened letter, or the attempted word; empty for hints and abandons.
+	Input string `json:"input,omitempty"`
+	// Correct is the outcome: whether the letter or word was right. Hints and reveals are always correct.
+	Correct bool `json:"correct"`
+	// TriesLeft is the number of tries left after the move.
+	TriesLeft int       `json:"tries_left"`
+	At        time.Time `json:"at"`
+}
+
+// Moves returns the game's move log, oldest first. Only moves that were accepted are logged.
+func (gameInstance *Game) Moves() []Move {
+	return append([]Move(nil), gameInstance.moves...)
+}
+
+// recordMove appends a move to the game's log. The log is append-only.
+func (gameInstance *Game) recordMove(kind MoveKind, input string, correct bool) {
+	gameInstance.moves = append(gameInstance.moves, Move{
+		Kind:      kind,
+		Input:     input,
+		Correct:   correct,
+		TriesLeft: gameInstance.MaxAttempts - gameInstance.IncorrectGuesses,
+		At:        time.Now(),
+	})
+}
+
+// errReplayDiverged is returned when a replayed move doesn't have the logged outcome.
+var errReplayDiverged = errors.New("replay diverged from the move log")
+
+// Replay rebuilds a game by playing a move log on a new game with the given setup. It checks
+// that every move has the logged outcome and keeps the logged timestamps, so replaying a game's
+// Setup and Moves yields an identical game.
+func Replay(setup GameSetup, moves []Move) (*Game, error) {
+	gameInstance := NewGame(setup)
+	for i, move := range moves {
+		correct, err := gameInstance.replayMove(move)
+		if err != nil {
+			return nil, fmt.Errorf("move %d (%s %q): %w", i+1, move.Kind, move.Input, err)
+		}
+		if correct != move.Correct {
+			return nil, fmt.Errorf("move %d (%s %q): %w", i+1, move.Kind, move.Input, errReplayDiverged)
+		}
+		gameInstance.moves[len(gameInstance.moves)-1].At = move.At
+	}
+	return gameInstance, nil
+}
+
+// replayMove applies one logged move and returns its outcome.
+func (gameInstance *Game) replayMove(move Move) (bool, error) {
+	switch move.Kind {
+	case MoveGuess:
+		letters := []rune(move.Input)
+		if len(letters) != 1 {
+			return false, fmt.Errorf("%w: %q", ErrInvalidLetter, move.Input)
+		}
+		return gameInstance.MakeGuess(letters[0])
+	case MoveOpenLetter:
+		letter, err := gameInstance.OpenLetter()
+		if err != nil {
+			return false, err
+		}
+		if string(letter) != move.Input {
+			return false, errReplayDiverged
+		}
+		return true, nil
+	case MoveHint:
+		_, err := gameInstance.ViewHint()
+		return err == nil, err
+	case MoveSolve:
+		return gameInstance.Solve(move.Input)
+	case MoveAbandon:
+		return false, gameInstance.Abandon()
+	default:
+		return false, fmt.Errorf("unknown move kind %q", move.Kind)
+	}
+}
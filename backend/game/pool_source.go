@@ -0,0 +1,257 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PoolStats reports the state of the prefetch pool for one language and word difficulty band.
+type PoolStats struct {
+	Language        string    `json:"language"`
+	MinDifficulty   float64   `json:"min_difficulty"`
+	MaxDifficulty   float64   `json:"max_difficulty"`
+	Depth           int       `json:"depth"`
+	Capacity        int       `json:"capacity"`
+	Refills         int       `json:"refills"`
+	RefillErrors    int       `json:"refill_errors"`
+	LastRefillError string    `json:"last_refill_error,omitempty"`
+	LastRefillAt    time.Time `json:"last_refill_at,omitzero"`
+}
+
+// PoolSource is a WordSource that keeps a buffered pool of prefetched words per language and
+// word difficulty band. A background refiller tops each pool up, so FetchRandomWord only waits
+// on the underlying source when the pool for the requested language and band is empty.
+type PoolSource struct {
+	source   WordSource
+	capacity int
+
+	mu    sync.Mutex
+	pools map[string]*wordPool
+
+	// ctx is cancelled by Close to stop the refillers and their in-flight fetches.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// wordPool is the prefetch buffer and refill bookkeeping for a single language and band.
+type wordPool struct {
+	query WordQuery
+	words chan *WordRecord
+	wake  chan struct{}
+
+	mu    sync.Mutex
+	stats PoolStats
+}
+
+var (
+	_ WordSource     = (*PoolSource)(nil)
+	_ CategoryLister = (*PoolSource)(nil)
+	_ HealthReporter = (*PoolSource)(nil)
+)
+
+// Refill backoff bounds used after the underlying source fails.
+const (
+	minRefillBackoff = time.Second
+	maxRefillBackoff = 30 * time.Second
+)
+
+// NewPoolSource creates a PoolSource of the given capacity and starts prefetching for the listed
+// queries (language and difficulty band). Other combinations get a pool the first time they are requested.
+func NewPoolSource(source WordSource, capacity int, prefetch ...WordQuery) *PoolSource {
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := &PoolSource{
+		source:   source,
+		capacity: capacity,
+		pools:    make(map[string]*wordPool),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	for _, query := range prefetch {
+		pool.poolFor(query)
+	}
+	return pool
+}
+
+// FetchRandomWord returns a prefetched word, falling back to the underlying source when the pool is empty.
+// Pools hold words of any category and length, so queries filtering on those always go to the
+// underlying source.
+func (source *PoolSource) FetchRandomWord(ctx context.Context, query WordQuery) (*WordRecord, error) {
+	if query.filtered() {
+		return source.source.FetchRandomWord(ctx, query)
+	}
+
+	pool := source.poolFor(query)
+	defer pool.signal()
+
+	if word := pool.take(query); word != nil {
+		served := *word
+		served.Language = query.Language
+		return &served, nil
+	}
+	return source.source.FetchRandomWord(ctx, query)
+}
+
+// take pops the first pooled word the query doesn't exclude, returning skipped words to the pool.
+// It returns nil without blocking if there is no such word.
+func (pool *wordPool) take(query WordQuery) *WordRecord {
+	var skipped []*WordRecord
+	defer func() {
+		for _, word := range skipped {
+			select {
+			case pool.words <- word:
+			default:
+			}
+		}
+	}()
+
+	for range cap(pool.words) {
+		select {
+		case word := <-pool.words:
+			if !query.excludes(word.Text) {
+				return word
+			}
+			skipped = append(skipped, word)
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// Categories lists the categories of the underlying source, if it can list them.
+func (source *PoolSource) Categories(ctx context.Context, lang string) ([]CategoryCount, error) {
+	lister, ok := source.source.(CategoryLister)
+	if !ok {
+		return []CategoryCount{}, nil
+	}
+	return lister.Categories(ctx, lang)
+}
+
+// Health reports the health of the underlying source, if it fails over between several sources.
+func (source *PoolSource) Health() []LinkHealth {
+	reporter, ok := source.source.(HealthReporter)
+	if !ok {
+		return []LinkHealth{}
+	}
+	return reporter.Health()
+}
+
+// Stats returns the depth and refill state of every language pool, ordered by language.
+func (source *PoolSource) Stats() []PoolStats {
+	source.mu.Lock()
+	pools := make([]*wordPool, 0, len(source.pools))
+	for _, pool := range source.pools {
+		pools = append(pools, pool)
+	}
+	source.mu.Unlock()
+
+	stats := make([]PoolStats, 0, len(pools))
+	for _, pool := range pools {
+		pool.mu.Lock()
+		poolStats := pool.stats
+		pool.mu.Unlock()
+		poolStats.Depth = len(pool.words)
+		stats = append(stats, poolStats)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Language != stats[j].Language {
+			return stats[i].Language < stats[j].Language
+		}
+		return stats[i].MinDifficulty < stats[j].MinDifficulty
+	})
+	return stats
+}
+
+// Close stops the background refillers.
+func (source *PoolSource) Close() {
+	source.cancel()
+}
+
+// poolFor returns the pool for a query's language and band, creating it and starting its refiller if needed.
+func (source *PoolSource) poolFor(query WordQuery) *wordPool {
+	poolQuery := WordQuery{
+		Language:      collectionFor(query.Language),
+		MinDifficulty: query.MinDifficulty,
+		MaxDifficulty: query.MaxDifficulty,
+	}
+	key := fmt.Sprintf("%s|%g|%g", poolQuery.Language, poolQuery.MinDifficulty, poolQuery.MaxDifficulty)
+
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	pool, exists := source.pools[key]
+	if !exists {
+		pool = &wordPool{
+			query: poolQuery,
+			words: make(chan *WordRecord, source.capacity),
+			wake:  make(chan struct{}, 1),
+			stats: PoolStats{
+				Language:      poolQuery.Language,
+				MinDifficulty: poolQuery.MinDifficulty,
+				MaxDifficulty: poolQuery.MaxDifficulty,
+				Capacity:      source.capacity,
+			},
+		}
+		source.pools[key] = pool
+		go source.refill(pool)
+	}
+	return pool
+}
+
+// refill keeps a pool topped up until the PoolSource is closed, backing off while the source fails.
+func (source *PoolSource) refill(pool *wordPool) {
+	backoff := minRefillBackoff
+	for {
+		for len(pool.words) < cap(pool.words) {
+			word, err := source.source.FetchRandomWord(source.ctx, pool.query)
+			if source.ctx.Err() != nil {
+				return
+			}
+			pool.record(err)
+			if err != nil {
+				if sleep(source.ctx, backoff) != nil {
+					return
+				}
+				backoff = min(backoff*2, maxRefillBackoff)
+				continue
+			}
+			backoff = minRefillBackoff
+			select {
+			case pool.words <- word:
+			default:
+			}
+		}
+
+		select {
+		case <-pool.wake:
+		case <-source.ctx.Done():
+			return
+		}
+	}
+}
+
+// signal wakes the refiller without blocking.
+func (pool *wordPool) signal() {
+	select {
+	case pool.wake <- struct{}{}:
+	default:
+	}
+}
+
+// record stores the outcome of a refill attempt in the pool's stats.
+func (pool *wordPool) record(err error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.stats.LastRefillAt = time.Now()
+	if err != nil {
+		pool.stats.RefillErrors++
+		pool.stats.LastRefillError = err.Error()
+		return
+	}
+	pool.stats.Refills++
+	pool.stats.LastRefillError = ""
+}
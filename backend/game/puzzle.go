@@ -0,0 +1,59 @@
+package game
+
+import (
+	"strings"
+	"unicode"
+)
+
+// hiddenCell is how a letter that hasn't been revealed yet is shown.
+const hiddenCell = "_"
+
+// WordSpan locates one word of a phrase within the cells of the displayed word.
+type WordSpan struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// IsSeparator reports whether a rune of a puzzle is a separator rather than a letter to guess.
+// Separators (spaces, hyphens, apostrophes, ...) are always revealed.
+func IsSeparator(char rune) bool {
+	return !unicode.IsLetter(char)
+}
+
+// RenderWord joins puzzle cells with spaces for display. A space between words is rendered as an
+// empty cell, so "ICE-CREAM TRUCK" shows as "_ _ _ - _ _ _ _ _  _ _ _ _ _" and splitting the
+// result on single spaces yields exactly one entry per cell.
+func RenderWord(cells []string) string {
+	rendered := make([]string, len(cells))
+	for i, cell := range cells {
+		if strings.TrimSpace(cell) == "" {
+			rendered[i] = ""
+		} else {
+			rendered[i] = cell
+		}
+	}
+	return strings.Join(rendered, " ")
+}
+
+// WordBoundaries returns the span of every whitespace-separated word of a phrase, in cell
+// positions. Hyphens and apostrophes stay inside their word.
+func WordBoundaries(targetWord string) []WordSpan {
+	var spans []WordSpan
+	start := -1
+	position := 0
+	for _, char := range targetWord {
+		if unicode.IsSpace(char) {
+			if start >= 0 {
+				spans = append(spans, WordSpan{Start: start, Length: position - start})
+				start = -1
+			}
+		} else if start < 0 {
+			start = position
+		}
+		position++
+	}
+	if start >= 0 {
+		spans = append(spans, WordSpan{Start: start, Length: position - start})
+	}
+	return spans
+}
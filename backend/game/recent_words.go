@@ -0,0 +1,121 @@
+package game
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// maxTrackedPlayers bounds the memory used by RecentWords; the least recently active players are forgotten first.
+const maxTrackedPlayers = 10000
+
+// RecentWords remembers the last words served to each player, per language, so that new games
+// can avoid repeating them. It is safe for concurrent use.
+type RecentWords struct {
+	window int
+
+	mu      sync.Mutex
+	players map[string]*list.Element
+	// activity lists the tracked players' histories, most recently active first.
+	activity *list.List
+}
+
+// playerHistory holds one player's recently served words, oldest first, per language collection.
+type playerHistory struct {
+	playerID string
+	words    map[string][]string
+}
+
+// NewRecentWords creates a tracker remembering the last window words per player and language.
+// A window of 0 disables tracking.
+func NewRecentWords(window int) *RecentWords {
+	return &RecentWords{window: window, players: make(map[string]*list.Element), activity: list.New()}
+}
+
+// NewRecentWordsFromEnv creates a tracker sized by NO_REPEAT_WINDOW (default 20).
+func NewRecentWordsFromEnv() (*RecentWords, error) {
+	window, err := intFromEnv("NO_REPEAT_WINDOW", 20)
+	if err != nil {
+		return nil, err
+	}
+	return NewRecentWords(max(window, 0)), nil
+}
+
+// Recent returns the words recently served to a player in a language, oldest first.
+func (recent *RecentWords) Recent(playerID, lang string) []string {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+
+	element, exists := recent.players[playerID]
+	if !exists {
+		return nil
+	}
+	return append([]string(nil), element.Value.(*playerHistory).words[collectionFor(lang)]...)
+}
+
+// Remember records that a word was served to a player.
+func (recent *RecentWords) Remember(playerID, lang, text string) {
+	if recent.window == 0 || playerID == "" {
+		return
+	}
+
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+
+	element, exists := recent.players[playerID]
+	if exists {
+		recent.activity.MoveToFront(element)
+	} else {
+		if len(recent.players) >= maxTrackedPlayers {
+			recent.evictOldest()
+		}
+		element = recent.activity.PushFront(&playerHistory{playerID: playerID, words: make(map[string][]string)})
+		recent.players[playerID] = element
+	}
+	history := element.Value.(*playerHistory)
+
+	key := collectionFor(lang)
+	words := append(history.words[key], normalizeWordKey(text))
+	if len(words) > recent.window {
+		words = words[len(words)-recent.window:]
+	}
+	history.words[key] = words
+}
+
+// FetchUnseenWord fetches a word matching the query that the player was not served recently,
+// and remembers it. If every matching word was served recently, the oldest half of the exclusion
+// list is dropped and the fetch retried, so words cycle from least to most recently seen.
+func (recent *RecentWords) FetchUnseenWord(ctx context.Context, source WordSource, playerID string, query WordQuery) (*WordRecord, error) {
+	exclude := recent.Recent(playerID, query.Language)
+	for {
+		query.Exclude = exclude
+		word, err := source.FetchRandomWord(ctx, query)
+		if errors.Is(err, ErrNoWords) && len(exclude) > 0 {
+			exclude = exclude[(len(exclude)+1)/2:]
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		recent.Remember(playerID, query.Language, word.Text)
+		return word, nil
+	}
+}
+
+// evictOldest forgets the least recently active player; the caller must hold recent.mu.
+func (recent *RecentWords) evictOldest() {
+	oldest := recent.activity.Back()
+	if oldest == nil {
+		return
+	}
+	recent.activity.Remove(oldest)
+	delete(recent.players, oldest.Value.(*playerHistory).playerID)
+}
+
+// normalizeWordKey puts a word in the form word banks store it: trimmed, normalized (see
+// NormalizeText) and upper-cased, so the same word from different sources compares equal.
+func normalizeWordKey(text string) string {
+	return strings.ToUpper(NormalizeText(strings.TrimSpace(text)))
+}
@@ -0,0 +1,103 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned while the circuit breaker rejects calls after repeated failures.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// RetryPolicy controls how idempotent reads are retried.
+type RetryPolicy struct {
+	Attempts  int           // total attempts, including the first
+	BaseDelay time.Duration // delay before the first retry
+	MaxDelay  time.Duration // upper bound for a single delay
+}
+
+// backoff returns the jittered delay before retry number attempt (starting at 1).
+// It uses "full jitter": a uniform delay between zero and the exponential bound.
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	bound := policy.BaseDelay << (attempt - 1)
+	if bound <= 0 || bound > policy.MaxDelay {
+		bound = policy.MaxDelay
+	}
+	if bound <= 0 {
+		return 0
+	}
+	return rand.N(bound) + 1
+}
+
+// sleep waits for the given delay or until the context is done.
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// circuitBreaker stops calls to a failing dependency.
+// After threshold consecutive failures it opens for cooldown; then a single probe call is let
+// through (half-open), and its outcome closes the breaker again or re-opens it.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// newCircuitBreaker creates a closed circuit breaker.
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports whether a call may proceed, returning ErrCircuitOpen if not.
+func (breaker *circuitBreaker) allow() error {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	if breaker.failures < breaker.threshold {
+		return nil
+	}
+	if time.Now().Before(breaker.openUntil) || breaker.probing {
+		return ErrCircuitOpen
+	}
+	breaker.probing = true
+	return nil
+}
+
+// release lets another probe through after an allowed call was abandoned, e.g. because its
+// context was cancelled, without counting it as a success or a failure.
+func (breaker *circuitBreaker) release() {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.probing = false
+}
+
+// record updates the breaker with the outcome of a call it allowed.
+func (breaker *circuitBreaker) record(success bool) {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.probing = false
+	if success {
+		breaker.failures = 0
+		return
+	}
+	breaker.failures++
+	if breaker.failures >= breaker.threshold {
+		breaker.openUntil = time.Now().Add(breaker.cooldown)
+	}
+}
@@ -0,0 +1,91 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ScoreWeights are the points a won game earns for each scoring factor. Every difficulty preset
+// has its own weights, so harder games can be worth more.
+type ScoreWeights struct {
+	// Win is awarded for winning at all.
+	Win int `json:"win"`
+	// PerTryLeft is awarded for each try left.
+	PerTryLeft int `json:"per_try_left"`
+	// PerRevealLeft is awarded for each open letter attempt left unused.
+	PerRevealLeft int `json:"per_reveal_left"`
+	// NoHint is awarded for winning without viewing the hint.
+	NoHint int `json:"no_hint"`
+	// Speed is awarded in full for an instant win and falls linearly to 0 at SpeedLimitSeconds.
+	Speed             int `json:"speed"`
+	SpeedLimitSeconds int `json:"speed_limit_seconds"`
+}
+
+// Score is the score of a finished game, broken down by factor. Lost and abandoned games score 0.
+type Score struct {
+	Total       int `json:"total"`
+	Win         int `json:"win"`
+	TriesLeft   int `json:"tries_left"`
+	RevealsLeft int `json:"reveals_left"`
+	NoHint      int `json:"no_hint"`
+	Speed       int `json:"speed"`
+}
+
+// Score returns the score of a finished game. It returns false while the game is in progress.
+func (gameInstance *Game) Score() (Score, bool) {
+	if !gameInstance.Status.IsFinished() {
+		return Score{}, false
+	}
+	if gameInstance.Status != StatusWon {
+		return Score{}, true
+	}
+
+	weights := gameInstance.setup.Scoring
+	score := Score{
+		Win:         weights.Win,
+		TriesLeft:   weights.PerTryLeft * (gameInstance.MaxAttempts - gameInstance.IncorrectGuesses),
+		RevealsLeft: weights.PerRevealLeft * gameInstance.OpenLetterAttempts,
+	}
+	if !gameInstance.HintViewed() {
+		score.NoHint = weights.NoHint
+	}
+	if finishedAt, ok := gameInstance.FinishedAt(); ok && weights.SpeedLimitSeconds > 0 {
+		limit := time.Duration(weights.SpeedLimitSeconds) * time.Second
+		remaining := 1 - float64(finishedAt.Sub(gameInstance.StartedAt))/float64(limit)
+		score.Speed = int(float64(weights.Speed) * clamp01(remaining))
+	}
+	score.Total = score.Win + score.TriesLeft + score.RevealsLeft + score.NoHint + score.Speed
+	return score, true
+}
+
+// LoadScoreWeightsFromEnv overrides the score weights of the difficulty presets from the JSON file
+// named by SCORE_WEIGHTS_FILE, if set. The file maps preset names to weights, e.g.
+// {"Hard": {"win": 500, "speed": 250}}; weights it leaves out keep their defaults.
+func LoadScoreWeightsFromEnv() error {
+	path := os.Getenv("SCORE_WEIGHTS_FILE")
+	if path == "" {
+		return nil
+	}
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read score weights: %w", err)
+	}
+
+	overrides := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(fileData, &overrides); err != nil {
+		return fmt.Errorf("failed to parse score weights: %w", err)
+	}
+	for name, override := range overrides {
+		preset, exists := difficultyPresets[name]
+		if !exists {
+			return fmt.Errorf("score weights for unknown difficulty %q", name)
+		}
+		if err := json.Unmarshal(override, &preset.Scoring); err != nil {
+			return fmt.Errorf("failed to parse score weights for %s: %w", name, err)
+		}
+		difficultyPresets[name] = preset
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package game
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	_ "modernc.org/sqlite" // registers the pure-Go "sqlite" driver
+)
+
+// sqliteSchema creates the words table. Words are indexed by language, category and length so
+// filtered random picks stay cheap; category is filled in by word banks that provide one, and
+// difficulty holds the word's WordDifficulty.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS words (
+	id         INTEGER PRIMARY KEY,
+	language   TEXT    NOT NULL,
+	category   TEXT    NOT NULL DEFAULT '',
+	text       TEXT    NOT NULL,
+	hint       TEXT    NOT NULL DEFAULT '',
+	length     INTEGER NOT NULL,
+	difficulty REAL    NOT NULL DEFAULT 0,
+	UNIQUE (language, text)
+);
+CREATE INDEX IF NOT EXISTS words_language_category_length ON words (language, category, length);
+`
+
+// SQLiteSource is a WordSource backed by a local SQLite database file.
+type SQLiteSource struct {
+	db *sql.DB
+}
+
+var (
+	_ WordSource     = (*SQLiteSource)(nil)
+	_ CategoryLister = (*SQLiteSource)(nil)
+)
+
+// OpenSQLiteSource opens (creating if needed) the SQLite word store at path.
+func OpenSQLiteSource(path string) (*SQLiteSource, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database %s: %w", path, err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create SQLite schema: %w", err)
+	}
+	if err := migrateSQLite(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate SQLite schema: %w", err)
+	}
+	return &SQLiteSource{db: db}, nil
+}
+
+// migrateSQLite brings word stores created before the difficulty column existed up to date.
+func migrateSQLite(db *sql.DB) error {
+	var hasDifficulty int
+	err := db.QueryRow(`SELECT count(*) FROM pragma_table_info('words') WHERE name = 'difficulty'`).Scan(&hasDifficulty)
+	if err != nil || hasDifficulty > 0 {
+		return err
+	}
+	if _, err := db.Exec(`ALTER TABLE words ADD COLUMN difficulty REAL NOT NULL DEFAULT 0`); err != nil {
+		return err
+	}
+
+	rows, err := db.Query(`SELECT id, language, text FROM words`)
+	if err != nil {
+		return err
+	}
+	difficulties := make(map[int64]float64)
+	for rows.Next() {
+		var id int64
+		var lang, text string
+		if err := rows.Scan(&id, &lang, &text); err != nil {
+			rows.Close()
+			return err
+		}
+		difficulties[id] = WordDifficulty(text, lang)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, difficulty := range difficulties {
+		if _, err := db.Exec(`UPDATE words SET difficulty = ? WHERE id = ?`, difficulty, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying database.
+func (source *SQLiteSource) Close() error {
+	return source.db.Close()
+}
+
+// CountWords returns the number of stored words across all languages.
+func (source *SQLiteSource) CountWords(ctx context.Context) (int, error) {
+	var count int
+	err := source.db.QueryRowContext(ctx, `SELECT count(*) FROM words`).Scan(&count)
+	return count, err
+}
+
+// ImportWordBank stores the words of a words.json word bank, skipping words already present
+// for their language. It returns how many words were added.
+func (source *SQLiteSource) ImportWordBank(ctx context.Context, wordBank map[string][]WordEntry) (int, error) {
+	tx, err := source.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	insert, err := tx.PrepareContext(ctx,
+		`INSERT OR IGNORE INTO words (language, category, text, hint, length, difficulty) VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer insert.Close()
+
+	imported := 0
+	for lang, entries := range wordBank {
+		for _, entry := range entries {
+			text := normalizeWordKey(entry.Text)
+			if text == "" {
+				continue
+			}
+			traits := measureWord(text, lang)
+			result, err := insert.ExecContext(ctx, lang, normalizeCategory(entry.Category), text, entry.Hint, traits.length, traits.difficulty)
+			if err != nil {
+				return 0, fmt.Errorf("failed to import %q: %w", text, err)
+			}
+			if added, _ := result.RowsAffected(); added > 0 {
+				imported++
+			}
+		}
+	}
+	return imported, tx.Commit()
+}
+
+// FetchRandomWord picks a uniformly random word matching the query in SQL.
+func (source *SQLiteSource) FetchRandomWord(ctx context.Context, query WordQuery) (*WordRecord, error) {
+	category := normalizeCategory(query.Category)
+	args := []any{
+		collectionFor(query.Language), category, category,
+		query.MinLength, query.MaxLength, query.MaxLength,
+		query.MinDifficulty, query.MaxDifficulty, query.MaxDifficulty,
+	}
+	excludeClause := ""
+	if len(query.Exclude) > 0 {
+		excludeClause = "AND text NOT IN (?" + strings.Repeat(", ?", len(query.Exclude)-1) + ")"
+		for _, text := range query.Exclude {
+			args = append(args, normalizeWordKey(text))
+		}
+	}
+
+	word := WordRecord{Language: query.Language, Source: "sqlite"}
+	err := source.db.QueryRowContext(ctx,
+		`SELECT text, hint, category FROM words
+		WHERE language = ? AND (? = '' OR category = ?)
+			AND length >= ? AND (? = 0 OR length <= ?)
+			AND difficulty >= ? AND (? = 0 OR difficulty <= ?)
+			`+excludeClause+`
+		ORDER BY random() LIMIT 1`,
+		args...,
+	).Scan(&word.Text, &word.Hint, &word.Category)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w for language %q", ErrNoWords, query.Language)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query SQLite word store: %w", err)
+	}
+	return &word, nil
+}
+
+// Categories counts the words per category of a language in SQL.
+func (source *SQLiteSource) Categories(ctx context.Context, lang string) ([]CategoryCount, error) {
+	rows, err := source.db.QueryContext(ctx,
+		`SELECT category, count(*) FROM words WHERE language = ? GROUP BY category`,
+		collectionFor(lang))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query SQLite word store: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var category string
+		var count int
+		if err := rows.Scan(&category, &count); err != nil {
+			return nil, err
+		}
+		counts[category] = count
+	}
+	return countCategories(counts), rows.Err()
+}
@@ -0,0 +1,41 @@
+package game
+
+// Status is where a game is in its lifecycle. A game starts InProgress and ends exactly once, as
+// Won, Lost or Abandoned; a finished game accepts no more moves.
+type Status string
+
+const (
+	StatusInProgress Status = "in_progress"
+	StatusWon        Status = "won"
+	StatusLost       Status = "lost"
+	StatusAbandoned  Status = "abandoned"
+)
+
+// IsFinished reports whether the status is final.
+func (status Status) IsFinished() bool {
+	return status != StatusInProgress
+}
+
+// Abandon ends an in-progress game without a result. Abandoning a finished game returns ErrGameOver.
+func (gameInstance *Game) Abandon() error {
+	if gameInstance.Status.IsFinished() {
+		return ErrGameOver
+	}
+	gameInstance.recordMove(MoveAbandon, "", false)
+	gameInstance.Status = StatusAbandoned
+	return nil
+}
+
+// updateStatus ends an in-progress game once its outcome is decided. A lost game reveals the word.
+func (gameInstance *Game) updateStatus() {
+	if gameInstance.Status != StatusInProgress {
+		return
+	}
+	switch {
+	case gameInstance.Mask.IsComplete():
+		gameInstance.Status = StatusWon
+	case gameInstance.IncorrectGuesses >= gameInstance.MaxAttempts:
+		gameInstance.Status = StatusLost
+		gameInstance.Mask.RevealAll()
+	}
+}
@@ -0,0 +1,245 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// ErrNoWords is returned (possibly wrapped) when a source has no word matching a query.
+var ErrNoWords = errors.New("no words match the query")
+
+// WordSource provides random words for new games.
+// Implementations can be swapped in main without touching the handlers.
+type WordSource interface {
+	// FetchRandomWord returns a random word matching the query.
+	// Implementations must stop waiting once ctx is done.
+	FetchRandomWord(ctx context.Context, query WordQuery) (*WordRecord, error)
+}
+
+// WordQuery describes the word a new game needs. Zero values leave a constraint unset.
+type WordQuery struct {
+	Language  string // "en", "pl", "ua" or "uk"
+	Category  string // optional theme such as "animals"; empty means any category
+	MinLength int    // minimum number of letters
+	MaxLength int    // maximum number of letters; 0 means no limit
+	// MinDifficulty and MaxDifficulty bound the WordDifficulty of the word; a zero
+	// MaxDifficulty means no upper bound.
+	MinDifficulty float64
+	MaxDifficulty float64
+	// Exclude lists words (case-insensitively) that must not be picked, e.g. recently played ones.
+	Exclude []string
+}
+
+// WithDifficulty returns the query constrained to the word difficulty band of a preset.
+func (query WordQuery) WithDifficulty(preset DifficultyPreset) WordQuery {
+	query.MinDifficulty = preset.MinWordDifficulty
+	query.MaxDifficulty = preset.MaxWordDifficulty
+	return query
+}
+
+// filtered reports whether the query narrows the choice by anything besides language, difficulty and exclusions.
+func (query WordQuery) filtered() bool {
+	return query.Category != "" || query.MinLength > 0 || query.MaxLength > 0
+}
+
+// excludes reports whether the query rules out a word.
+func (query WordQuery) excludes(text string) bool {
+	key := normalizeWordKey(text)
+	for _, excluded := range query.Exclude {
+		if normalizeWordKey(excluded) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether a word of the query's language satisfies the query's filters.
+func (query WordQuery) matches(text, category string, traits wordTraits) bool {
+	if query.excludes(text) {
+		return false
+	}
+	if query.Category != "" && normalizeCategory(query.Category) != category {
+		return false
+	}
+	if traits.length < query.MinLength || (query.MaxLength > 0 && traits.length > query.MaxLength) {
+		return false
+	}
+	if traits.difficulty < query.MinDifficulty || (query.MaxDifficulty > 0 && traits.difficulty > query.MaxDifficulty) {
+		return false
+	}
+	return true
+}
+
+// CategoryCount is the number of words available in one category.
+type CategoryCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// CategoryLister is implemented by word sources that can list the categories of a language.
+type CategoryLister interface {
+	// Categories returns the categories of a language with their word counts, ordered by name.
+	Categories(ctx context.Context, lang string) ([]CategoryCount, error)
+}
+
+// HealthReporter is implemented by word sources that fail over between several sources.
+type HealthReporter interface {
+	// Health returns a snapshot of the health of every source taking part, in failover order.
+	Health() []LinkHealth
+}
+
+// normalizeCategory folds a category name so "Animals " and "animals" are the same category.
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+// countCategories turns per-category counts into a sorted CategoryCount list, skipping uncategorized words.
+func countCategories(counts map[string]int) []CategoryCount {
+	categories := make([]CategoryCount, 0, len(counts))
+	for name, count := range counts {
+		if name != "" {
+			categories = append(categories, CategoryCount{Name: name, Count: count})
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories
+}
+
+// WordSourceName returns the name of the word source selected by the environment: WORD_SOURCE,
+// or else firestore when FIREBASE_PROJECT_ID is set and local otherwise.
+func WordSourceName() string {
+	if sourceName := os.Getenv("WORD_SOURCE"); sourceName != "" {
+		return sourceName
+	}
+	if os.Getenv("FIREBASE_PROJECT_ID") != "" {
+		return "firestore"
+	}
+	return "local"
+}
+
+// NewWordSourceFromEnv builds the WordSource selected by the WORD_SOURCE environment variable.
+//
+//   - "firestore": words are fetched from Firestore (requires FIREBASE_PROJECT_ID), failing over
+//     to the embedded word bank while Firestore is unavailable.
+//   - "local": words are read from WORDS_FILE_PATH, or from the embedded word bank if it is unset.
+//   - "sqlite": words are read from the SQLite database at SQLITE_PATH (default "words.db"),
+//     which is filled from the embedded word bank when it is empty.
+//
+// When WORD_SOURCE is empty, Firestore is used if FIREBASE_PROJECT_ID is set and the local bank otherwise.
+// WORD_SOURCE_COOLDOWN and WORD_SOURCE_TIMEOUT tune the Firestore failover, and Firestore words are
+// prefetched into pools of WORD_POOL_SIZE words for each difficulty preset of every language in
+// WORD_POOL_LANGUAGES (0 disables the pools).
+func NewWordSourceFromEnv() (WordSource, error) {
+	_ = godotenv.Load()
+
+	switch sourceName := WordSourceName(); sourceName {
+	case "firestore":
+		firestore, err := NewFirestoreSource()
+		if err != nil {
+			return nil, err
+		}
+		embedded, err := NewEmbeddedSource()
+		if err != nil {
+			return nil, err
+		}
+		cooldown, err := durationFromEnv("WORD_SOURCE_COOLDOWN", 30*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		timeout, err := durationFromEnv("WORD_SOURCE_TIMEOUT", 3*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		chain := NewChainSource(cooldown, timeout,
+			ChainLink{Name: "firestore", Source: firestore},
+			ChainLink{Name: "embedded", Source: embedded},
+		)
+		poolSize, err := intFromEnv("WORD_POOL_SIZE", 10)
+		if err != nil {
+			return nil, err
+		}
+		if poolSize <= 0 {
+			return chain, nil
+		}
+		poolLanguages := os.Getenv("WORD_POOL_LANGUAGES")
+		if poolLanguages == "" {
+			poolLanguages = "en,pl,ua"
+		}
+		var prefetch []WordQuery
+		for _, lang := range strings.Split(poolLanguages, ",") {
+			for _, preset := range DifficultyPresets() {
+				prefetch = append(prefetch, WordQuery{Language: strings.TrimSpace(lang)}.WithDifficulty(preset))
+			}
+		}
+		return NewPoolSource(chain, poolSize, prefetch...), nil
+	case "local":
+		if wordsFilePath := os.Getenv("WORDS_FILE_PATH"); wordsFilePath != "" {
+			return NewFileSource(wordsFilePath)
+		}
+		return NewEmbeddedSource()
+	case "sqlite":
+		sqlitePath := os.Getenv("SQLITE_PATH")
+		if sqlitePath == "" {
+			sqlitePath = "words.db"
+		}
+		return openSeededSQLiteSource(sqlitePath)
+	default:
+		return nil, fmt.Errorf("unknown WORD_SOURCE %q", sourceName)
+	}
+}
+
+// openSeededSQLiteSource opens a SQLite word store and imports the embedded word bank if it has no words yet.
+func openSeededSQLiteSource(path string) (*SQLiteSource, error) {
+	source, err := OpenSQLiteSource(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	count, err := source.CountWords(ctx)
+	if err == nil && count == 0 {
+		var wordBank map[string][]WordEntry
+		if wordBank, err = ParseWordBank(embeddedWordBank); err == nil {
+			_, err = source.ImportWordBank(ctx, wordBank)
+		}
+	}
+	if err != nil {
+		source.Close()
+		return nil, err
+	}
+	return source, nil
+}
+
+// durationFromEnv parses a duration such as "30s" from an environment variable, returning fallback if it is unset.
+func durationFromEnv(name string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return duration, nil
+}
+
+// intFromEnv parses an integer from an environment variable, returning fallback if it is unset.
+func intFromEnv(name string, fallback int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return number, nil
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	game "hangman/backend/game"
+)
+
+// Errors of the handler layer itself; errors of the game rules come from the game package.
+var (
+	errInvalidRequest        = errors.New("invalid request")
+	errInvalidLengthRange    = errors.New("invalid word length range")
+	errInvalidSessionID      = errors.New("invalid session ID")
+	errSessionNotFound       = errors.New("session not found")
+	errWordSourceUnavailable = errors.New("word source unavailable")
+	errPageNotFound          = errors.New("page not found")
+)
+
+// ErrorResponse is the body of every error response. Code is stable and meant for clients to
+// switch on; Message is for people; Details carries extras such as the offending request field.
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details gin.H  `json:"details,omitempty"`
+}
+
+// errorMapping ties an error to the HTTP status and code it is reported with.
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// errorMappings lists the errors the API reports, matched in order with errors.Is.
+var errorMappings = []errorMapping{
+	{game.ErrGameOver, http.StatusConflict, "GAME_OVER"},
+	{game.ErrAlreadyGuessed, http.StatusConflict, "ALREADY_GUESSED"},
+	{game.ErrNoRevealsLeft, http.StatusConflict, "NO_REVEALS_LEFT"},
+	{game.ErrNoLettersToOpen, http.StatusConflict, "NO_LETTERS_TO_OPEN"},
+	{game.ErrInvalidLetter, http.StatusBadRequest, "INVALID_LETTER"},
+	{game.ErrUnsupportedLanguage, http.StatusBadRequest, "UNSUPPORTED_LANGUAGE"},
+	{game.ErrNoWords, http.StatusNotFound, "NO_WORDS"},
+	{errInvalidRequest, http.StatusBadRequest, "INVALID_REQUEST"},
+	{errInvalidLengthRange, http.StatusBadRequest, "INVALID_LENGTH_RANGE"},
+	{errInvalidSessionID, http.StatusBadRequest, "INVALID_SESSION_ID"},
+	{errSessionNotFound, http.StatusNotFound, "SESSION_NOT_FOUND"},
+	{errWordSourceUnavailable, http.StatusServiceUnavailable, "WORD_SOURCE_UNAVAILABLE"},
+	{errPageNotFound, http.StatusNotFound, "PAGE_NOT_FOUND"},
+}
+
+// respondError answers with the status and code an error maps to, its message and optional
+// details. Errors without a mapping are logged and reported as INTERNAL_ERROR without their text.
+func respondError(c *gin.Context, err error, details gin.H) {
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.err) {
+			c.JSON(mapping.status, ErrorResponse{Code: mapping.code, Message: errorMessage(err), Details: details})
+			return
+		}
+	}
+	log.Printf("%s %s failed: %v", c.Request.Method, c.Request.URL.Path, err)
+	c.JSON(http.StatusInternalServerError, ErrorResponse{Code: "INTERNAL_ERROR", Message: "Internal server error"})
+}
+
+// errorMessage turns an error into a message for people: its text, capitalized.
+func errorMessage(err error) string {
+	text := err.Error()
+	first, size := utf8.DecodeRuneInString(text)
+	return string(unicode.ToUpper(first)) + text[size:]
+}
+
+// NotFound answers requests for routes that don't exist.
+func NotFound(c *gin.Context) {
+	respondError(c, errPageNotFound, nil)
+}
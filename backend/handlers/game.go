@@ -1,52 +1,104 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	game "hangman/backend/game"
 	manager "hangman/backend/session"
+	"log"
 	"net/http"
-	"regexp"
 	"strings"
-	"unicode"
+	"time"
 )
 
 var sm *manager.SessionManager
+var words game.WordSource
+var recentWords *game.RecentWords
 
-func NewGameHandler(sessionManager *manager.SessionManager) {
+// playerCookie is the cookie identifying a player when the request carries no player_id.
+const playerCookie = "hangman_player"
+
+// NewGameHandler wires the session manager, the word source and the recent-word tracker used by the handlers.
+func NewGameHandler(sessionManager *manager.SessionManager, wordSource game.WordSource, recent *game.RecentWords) {
 	sm = sessionManager
+	words = wordSource
+	recentWords = recent
 }
 
 type NewGameRequest struct {
-	Language   string `json:"language"`   // "en", "pl", "ua"
+	Language   string `json:"language"`   // "en", "pl", "uk" (or an alias such as "ua")
 	Difficulty string `json:"difficulty"` // "Easy", "Normal", Hard"
+	Category   string `json:"category"`   // optional theme, e.g. "animals"
+	MinLength  int    `json:"min_length"` // optional minimum number of letters
+	MaxLength  int    `json:"max_length"` // optional maximum number of letters
+	PlayerID   string `json:"player_id"`  // optional stable player ID; falls back to the player cookie
+	// DiacriticTolerant is optional: base letters also reveal their accented variants ("l" reveals "ł").
+	DiacriticTolerant bool `json:"diacritic_tolerant"`
 }
 
 type NewGameResponse struct {
-	SessionID          uuid.UUID `json:"session_id"`
-	WordLength         int       `json:"word_length"`
-	MaxAttempts        int       `json:"max_attempts"`
-	OpenLetterAttempts int       `json:"open_letter_attempts"`
+	SessionID          uuid.UUID       `json:"session_id"`
+	WordLength         int             `json:"word_length"`
+	CurrentWord        string          `json:"current_word"` // masked word, separators already revealed
+	WordBoundaries     []game.WordSpan `json:"word_boundaries"`
+	MaxAttempts        int             `json:"max_attempts"`
+	OpenLetterAttempts int             `json:"open_letter_attempts"`
+	WordSource         string          `json:"word_source"`
+	Category           string          `json:"category,omitempty"`
+	PlayerID           string          `json:"player_id"`
+	DiacriticTolerant  bool            `json:"diacritic_tolerant"`
+	// WordDifficulty is the difficulty whose word band the word was drawn from. It differs from the
+	// requested difficulty, and DifficultyRelaxed is set, when no word matched the requested band.
+	WordDifficulty    string `json:"word_difficulty"`
+	DifficultyRelaxed bool   `json:"difficulty_relaxed"`
 }
 
 type GuessRequest struct {
 	Letter string `json:"letter"`
 }
 
+type SolveRequest struct {
+	Word string `json:"word"`
+}
+
 type GuessResponse struct {
-	Correct     bool   `json:"correct"`
-	CurrentWord string `json:"current_word"`
-	TriesLeft   int    `json:"tries_left"`
-	IsGameOver  bool   `json:"is_game_over"`
-	IsWon       bool   `json:"won"`
-	OpenedLetter string `json:"opened_letter,omitempty"`
+	Correct      bool        `json:"correct"`
+	CurrentWord  string      `json:"current_word"`
+	TriesLeft    int         `json:"tries_left"`
+	IsGameOver   bool        `json:"is_game_over"`
+	IsWon        bool        `json:"won"`
+	Status       game.Status `json:"status"`
+	OpenedLetter string      `json:"opened_letter,omitempty"`
+	// TransliteratedFrom is the Latin input a guess was read from; OpenedLetter is the letter it stands for.
+	TransliteratedFrom string `json:"transliterated_from,omitempty"`
+	// Score is only included once the game is over.
+	Score *game.Score `json:"score,omitempty"`
 }
 
+// GameStateResponse is the full state of a game, enough for a client to restore it after a reload.
 type GameStateResponse struct {
-	CurrentWord string `json:"current_word"`
-	TriesLeft   int    `json:"tries_left"`
-	IsGameOver  bool   `json:"is_game_over"`
-	IsWon       bool   `json:"won"`
+	CurrentWord        string          `json:"current_word"`
+	WordBoundaries     []game.WordSpan `json:"word_boundaries"`
+	WordLength         int             `json:"word_length"`
+	TriesLeft          int             `json:"tries_left"`
+	MaxAttempts        int             `json:"max_attempts"`
+	OpenLetterAttempts int             `json:"open_letter_attempts"` // remaining
+	CorrectLetters     []string        `json:"correct_letters"`
+	WrongLetters       []string        `json:"wrong_letters"`
+	HintViewed         bool            `json:"hint_viewed"`
+	Language           string          `json:"language"`
+	Difficulty         string          `json:"difficulty"`
+	DiacriticTolerant  bool            `json:"diacritic_tolerant"`
+	IsGameOver         bool            `json:"is_game_over"`
+	IsWon              bool            `json:"won"`
+	Status             game.Status     `json:"status"`
+	StartedAt          time.Time       `json:"started_at"`
+	FinishedAt         *time.Time      `json:"finished_at,omitempty"`
+	// Word and Score are only included once the game is over.
+	Word  string      `json:"word,omitempty"`
+	Score *game.Score `json:"score,omitempty"`
 }
 
 // NewGame handles the creation of a new game session.
@@ -54,46 +106,76 @@ func NewGame(c *gin.Context) {
 	var req NewGameRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		respondError(c, errInvalidRequest, nil)
+		return
+	}
+	if req.MinLength < 0 || req.MaxLength < 0 || (req.MaxLength > 0 && req.MinLength > req.MaxLength) {
+		respondError(c, errInvalidLengthRange, gin.H{"field": "min_length", "min_length": req.MinLength, "max_length": req.MaxLength})
+		return
+	}
+	language, ok := game.LookupLanguage(req.Language)
+	if !ok {
+		respondError(c, fmt.Errorf("%w %q", game.ErrUnsupportedLanguage, req.Language), gin.H{"field": "language"})
+		return
+	}
+	// Determine max attempts, openLetter attempts and the word difficulty band based on difficulty
+	preset := game.LookupDifficulty(req.Difficulty)
+	maxAttempts, openLetterAttempts := preset.MaxAttempts, preset.OpenLetterAttempts
+
+	playerID := resolvePlayerID(c, req.PlayerID)
+	query := game.WordQuery{
+		Language:  language.Code,
+		Category:  req.Category,
+		MinLength: req.MinLength,
+		MaxLength: req.MaxLength,
+	}
+	// When the player's own filters leave no word in the difficulty's band, fall back to the nearest
+	// band rather than failing, and say so in the response.
+	var word *game.WordRecord
+	var wordPreset game.DifficultyPreset
+	err := game.ErrNoWords
+	for _, wordPreset = range game.NearestPresets(preset) {
+		word, err = recentWords.FetchUnseenWord(c.Request.Context(), words, playerID, query.WithDifficulty(wordPreset))
+		if !errors.Is(err, game.ErrNoWords) {
+			break
+		}
+	}
+	if errors.Is(err, game.ErrNoWords) {
+		respondError(c, err, gin.H{"language": language.Code, "category": req.Category, "min_length": req.MinLength, "max_length": req.MaxLength})
 		return
 	}
-	// Determine max attempts and openLetter attempts based on difficulty
-	var maxAttempts, openLetterAttempts int
-	switch req.Difficulty {
-	case "Hard":
-		maxAttempts = 3
-		openLetterAttempts = 0
-	case "Normal":
-		maxAttempts = 5
-		openLetterAttempts = 1
-	case "Easy":
-		maxAttempts = 7
-		openLetterAttempts = 2
-	default:
-		maxAttempts = 7
-		openLetterAttempts = 2
-	}
-
-	word, err := game.FetchRandomWord(req.Language)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word"})
+		log.Printf("Failed to fetch word: %v", err)
+		respondError(c, errWordSourceUnavailable, nil)
 		return
 	}
 	// Create a new game instance
-	gameInstance := game.NewGame(word.Text, word.Hint, maxAttempts, openLetterAttempts, req.Language)
+	gameInstance := game.NewGame(game.GameSetup{
+		TargetWord:         word.Text,
+		Hint:               word.Hint,
+		MaxAttempts:        maxAttempts,
+		OpenLetterAttempts: openLetterAttempts,
+		Language:           language.Code,
+		Difficulty:         preset.Name,
+		DiacriticTolerant:  req.DiacriticTolerant,
+		SolvePenalty:       preset.SolvePenalty,
+		Scoring:            preset.Scoring,
+	})
 	// Create a new session for the game
 	sessionID := sm.CreateSession(gameInstance)
-	letterCount := 0
-	for _, char := range word.Text {
-		if unicode.IsLetter(char) {
-			letterCount++
-		}
-	}
 	resp := NewGameResponse{
 		SessionID:          sessionID,
-		WordLength:         letterCount,
+		WordLength:         gameInstance.WordLength(),
+		CurrentWord:        game.GetDisplayWord(gameInstance),
+		WordBoundaries:     game.WordBoundaries(gameInstance.TargetWord),
 		MaxAttempts:        maxAttempts,
 		OpenLetterAttempts: openLetterAttempts,
+		WordSource:         word.Source,
+		Category:           word.Category,
+		PlayerID:           playerID,
+		DiacriticTolerant:  gameInstance.DiacriticTolerant,
+		WordDifficulty:     wordPreset.Name,
+		DifficultyRelaxed:  wordPreset.Name != preset.Name,
 	}
 	c.JSON(http.StatusOK, resp)
 }
@@ -102,175 +184,300 @@ func NewGame(c *gin.Context) {
 func MakeGuess(c *gin.Context) {
 	var req GuessRequest
 
-	gameInstance, ok := getGameInstance(c)
+	gameInstance, unlock, ok := getGameInstance(c)
 	if !ok {
 		return
 	}
+	defer unlock()
 
 	if err := c.ShouldBindJSON(&req); err != nil || len(req.Letter) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guess"})
+		respondError(c, errInvalidRequest, gin.H{"field": "letter"})
 		return
 	}
 
-	if gameInstance.Language == "ua" {
-		matched, _ := regexp.MatchString(`^[\p{Cyrillic}']$`, req.Letter)
-		if !matched {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid letter for Ukrainian language"})
-			return
-		}
+	// Latin input may stand for a letter of a non-Latin alphabet, e.g. "sh" for "ш" in Ukrainian.
+	language, _ := game.LookupLanguage(gameInstance.Language)
+	letter, transliterated, err := language.ParseLetter(req.Letter)
+	if err != nil {
+		respondError(c, err, gin.H{"field": "letter", "language": language.Code})
+		return
+	}
+	correct, err := gameInstance.MakeGuess(letter)
+	if err != nil {
+		respondError(c, err, gin.H{"field": "letter", "letter": string(letter), "status": gameInstance.Status})
+		return
 	}
-
-	letterToRunes := []rune(req.Letter)
-	letter := unicode.ToLower(letterToRunes[0])
-	correct := gameInstance.MakeGuess(letter)
 
 	isGameOver := gameInstance.IsGameOver()
-	isWon := game.IsWordGuessed(gameInstance.CurrentWordState, gameInstance.TargetWord)
-
-	if isGameOver && !isWon {
-		openAllLetters(gameInstance)
-	}
+	isWon := gameInstance.IsWon()
 
 	resp := GuessResponse{
-		Correct:     correct,
-		CurrentWord: strings.Join(gameInstance.CurrentWordState, " "),
-		TriesLeft:   gameInstance.MaxAttempts - gameInstance.IncorrectGuesses,
-		IsGameOver:  isGameOver,
-		IsWon:       isWon,
+		Correct:      correct,
+		CurrentWord:  game.GetDisplayWord(gameInstance),
+		TriesLeft:    gameInstance.MaxAttempts - gameInstance.IncorrectGuesses,
+		IsGameOver:   isGameOver,
+		IsWon:        isWon,
+		Status:       gameInstance.Status,
+		Score:        finalScore(gameInstance),
 		OpenedLetter: string(letter),
 	}
+	if transliterated {
+		resp.TransliteratedFrom = req.Letter
+	}
 	c.JSON(http.StatusOK, resp)
 }
 
-// GetState retrieves the current state of a specific game session.
-func GetState(c *gin.Context) {
-	gameInstance, ok := getGameInstance(c)
+// Solve handles a whole-word guess for a specific game session.
+func Solve(c *gin.Context) {
+	var req SolveRequest
+
+	gameInstance, unlock, ok := getGameInstance(c)
 	if !ok {
 		return
 	}
+	defer unlock()
+
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Word) == "" {
+		respondError(c, errInvalidRequest, gin.H{"field": "word"})
+		return
+	}
+
+	correct, err := gameInstance.Solve(req.Word)
+	if err != nil {
+		respondError(c, err, gin.H{"status": gameInstance.Status})
+		return
+	}
 
 	isGameOver := gameInstance.IsGameOver()
-	isWon := game.IsWordGuessed(gameInstance.CurrentWordState, gameInstance.TargetWord)
+	isWon := gameInstance.IsWon()
 
-	resp := GameStateResponse{
-		CurrentWord: strings.Join(gameInstance.CurrentWordState, " "),
+	resp := GuessResponse{
+		Correct:     correct,
+		CurrentWord: game.GetDisplayWord(gameInstance),
 		TriesLeft:   gameInstance.MaxAttempts - gameInstance.IncorrectGuesses,
 		IsGameOver:  isGameOver,
 		IsWon:       isWon,
+		Status:      gameInstance.Status,
+		Score:       finalScore(gameInstance),
 	}
 	c.JSON(http.StatusOK, resp)
 }
 
+// GetState retrieves the current state of a specific game session.
+func GetState(c *gin.Context) {
+	gameInstance, unlock, ok := getGameInstance(c)
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	c.JSON(http.StatusOK, newGameStateResponse(gameInstance))
+}
+
 // GetHint provides the hint for the target word in a specific game session.
 func GetHint(c *gin.Context) {
-	gameInstance, ok := getGameInstance(c)
+	gameInstance, unlock, ok := getGameInstance(c)
 	if !ok {
 		return
 	}
+	defer unlock()
+
+	hint, err := gameInstance.ViewHint()
+	if err != nil {
+		respondError(c, err, gin.H{"status": gameInstance.Status})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"hint": gameInstance.Hint})
+	c.JSON(http.StatusOK, gin.H{"hint": hint})
+}
+
+// GetMoves lists the moves made in a specific game session, oldest first.
+func GetMoves(c *gin.Context) {
+	gameInstance, unlock, ok := getGameInstance(c)
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	c.JSON(http.StatusOK, gin.H{"moves": gameInstance.Moves(), "status": gameInstance.Status})
+}
+
+// Abandon gives up on a specific game session, ending it without a result.
+func Abandon(c *gin.Context) {
+	gameInstance, unlock, ok := getGameInstance(c)
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	if err := gameInstance.Abandon(); err != nil {
+		respondError(c, err, gin.H{"status": gameInstance.Status})
+		return
+	}
+
+	c.JSON(http.StatusOK, newGameStateResponse(gameInstance))
 }
 
 // OpenLetter opens one unguessed letter from the target word in a specific game session.
 func OpenLetter(c *gin.Context) {
-	var openedLetter string
-	gameInstance, ok := getGameInstance(c)
+	gameInstance, unlock, ok := getGameInstance(c)
 	if !ok {
 		return
 	}
+	defer unlock()
 
-	if gameInstance.MaxAttempts-gameInstance.IncorrectGuesses <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No more attempts left"})
+	// Open all occurrences of the first closed letter
+	openedLetter, err := gameInstance.OpenLetter()
+	if err != nil {
+		respondError(c, err, gin.H{"status": gameInstance.Status, "open_letter_attempts": gameInstance.OpenLetterAttempts})
 		return
 	}
-	if gameInstance.OpenLetterAttempts <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No more open letter attempts left"})
+	isGameOver := gameInstance.IsGameOver()
+	isWon := gameInstance.IsWon()
+
+	resp := GuessResponse{
+		Correct:      true,
+		CurrentWord:  game.GetDisplayWord(gameInstance),
+		TriesLeft:    gameInstance.MaxAttempts - gameInstance.IncorrectGuesses,
+		IsGameOver:   isGameOver,
+		IsWon:        isWon,
+		Status:       gameInstance.Status,
+		Score:        finalScore(gameInstance),
+		OpenedLetter: string(openedLetter),
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetLanguages lists the supported languages with their alphabets and vowels.
+func GetLanguages(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"languages": game.Languages()})
+}
+
+// GetCategories lists the word categories available for a language, with their word counts.
+func GetCategories(c *gin.Context) {
+	language, ok := game.LookupLanguage(c.Query("language"))
+	if !ok {
+		respondError(c, fmt.Errorf("%w %q", game.ErrUnsupportedLanguage, c.Query("language")), gin.H{"field": "language"})
 		return
 	}
 
-	// Find the first closed letter to open
-	openedLetter = findLetterToOpen(gameInstance)
-	if openedLetter == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No letters to open"})
+	lister, ok := words.(game.CategoryLister)
+	if !ok {
+		c.JSON(http.StatusOK, gin.H{"language": language.Code, "categories": []game.CategoryCount{}})
+		return
+	}
+	categories, err := lister.Categories(c.Request.Context(), language.Code)
+	if err != nil {
+		log.Printf("Failed to list categories: %v", err)
+		respondError(c, errWordSourceUnavailable, nil)
 		return
 	}
 
-	// Open all occurrences of the opened letter
-	openAllSameClosedLetters(gameInstance, openedLetter)
-	gameInstance.GuessedLetters[rune(openedLetter[0])] = true
-	gameInstance.OpenLetterAttempts--
-	gameInstance.IncorrectGuesses++
-	isGameOver := gameInstance.IsGameOver()
-	isWon := game.IsWordGuessed(gameInstance.CurrentWordState, gameInstance.TargetWord)
+	c.JSON(http.StatusOK, gin.H{"language": language.Code, "categories": categories})
+}
 
-	// If the game is over and the player has lost, open all remaining letters to reveal the word.
-	if isGameOver && !isWon {
-		openAllLetters(gameInstance)
+// GetWordPoolStats reports the depth and refill errors of the word prefetch pools.
+func GetWordPoolStats(c *gin.Context) {
+	pool, ok := words.(*game.PoolSource)
+	if !ok {
+		c.JSON(http.StatusOK, gin.H{"pools": []game.PoolStats{}})
+		return
 	}
 
-	resp := GuessResponse{
-		Correct:     true,
-		CurrentWord: strings.Join(gameInstance.CurrentWordState, " "),
-		TriesLeft:   gameInstance.MaxAttempts - gameInstance.IncorrectGuesses,
-		IsGameOver:  isGameOver,
-		IsWon:       isWon,
-		OpenedLetter: openedLetter,
+	c.JSON(http.StatusOK, gin.H{"pools": pool.Stats()})
+}
+
+// GetWordSourceHealth reports the health of each link in the word source failover chain.
+func GetWordSourceHealth(c *gin.Context) {
+	reporter, ok := words.(game.HealthReporter)
+	if !ok {
+		c.JSON(http.StatusOK, gin.H{"sources": []game.LinkHealth{}})
+		return
 	}
-	c.JSON(http.StatusOK, resp)
+
+	c.JSON(http.StatusOK, gin.H{"sources": reporter.Health()})
 }
 
-// openAllLetters is a helper function that opens all letters in the target word, used when the game is over and the player has lost.
-func openAllLetters(gameInstance *game.Game) {
-	stateIndex := 0
-	for _, char := range gameInstance.TargetWord {
-		if unicode.IsLetter(char) {
-			gameInstance.CurrentWordState[stateIndex] = string(char)
-			stateIndex++
-		}
+// newGameStateResponse is a helper function that builds the full state of a game.
+func newGameStateResponse(gameInstance *game.Game) GameStateResponse {
+	resp := GameStateResponse{
+		CurrentWord:        game.GetDisplayWord(gameInstance),
+		WordBoundaries:     game.WordBoundaries(gameInstance.TargetWord),
+		WordLength:         gameInstance.WordLength(),
+		TriesLeft:          gameInstance.MaxAttempts - gameInstance.IncorrectGuesses,
+		MaxAttempts:        gameInstance.MaxAttempts,
+		OpenLetterAttempts: gameInstance.OpenLetterAttempts,
+		CorrectLetters:     gameInstance.CorrectLetters(),
+		WrongLetters:       gameInstance.WrongLetters(),
+		HintViewed:         gameInstance.HintViewed(),
+		Language:           gameInstance.Language,
+		Difficulty:         gameInstance.Difficulty,
+		DiacriticTolerant:  gameInstance.DiacriticTolerant,
+		IsGameOver:         gameInstance.IsGameOver(),
+		IsWon:              gameInstance.IsWon(),
+		Status:             gameInstance.Status,
+		StartedAt:          gameInstance.StartedAt,
 	}
+	if finishedAt, ok := gameInstance.FinishedAt(); ok {
+		resp.FinishedAt = &finishedAt
+	}
+	if gameInstance.IsGameOver() {
+		resp.Word = gameInstance.TargetWord
+	}
+	resp.Score = finalScore(gameInstance)
+	return resp
 }
 
-// findLetterToOpen is a helper function that finds the first closed letter in the target word and returns it.
-func findLetterToOpen(gameInstance *game.Game) string {
-	for i, char := range gameInstance.TargetWord {
-		if gameInstance.CurrentWordState[i] == "_" && unicode.IsLetter(char) {
-			return string(char)
-		}
+// finalScore is a helper function that returns the score of a finished game, or nil while it is in progress.
+func finalScore(gameInstance *game.Game) *game.Score {
+	score, ok := gameInstance.Score()
+	if !ok {
+		return nil
 	}
-	return ""
+	return &score
 }
 
-// openAllSameClosedLetters is a helper function that opens all occurrences of a specific letter in the target word.
-func openAllSameClosedLetters(gameInstance *game.Game, letter string) {
-	stateIndex := 0
-	for _, char := range gameInstance.TargetWord {
-		if unicode.IsLetter(char) && string(char) == letter {
-			gameInstance.CurrentWordState[stateIndex] = string(char)
-		}
-		if unicode.IsLetter(char) {
-			stateIndex++
-		}
+// resolvePlayerID is a helper function that identifies the player by the requested ID, the player
+// cookie, or a newly generated ID, and (re)sets the cookie so the ID sticks across games.
+func resolvePlayerID(c *gin.Context, requestedID string) string {
+	playerID := strings.TrimSpace(requestedID)
+	if playerID == "" {
+		playerID, _ = c.Cookie(playerCookie)
+	}
+	if playerID == "" {
+		playerID = uuid.NewString()
+	}
+
+	// The frontend is usually served from another site, so the cookie must be SameSite=None (and thus Secure) over HTTPS.
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	if secure {
+		c.SetSameSite(http.SameSiteNoneMode)
+	} else {
+		c.SetSameSite(http.SameSiteLaxMode)
 	}
+	c.SetCookie(playerCookie, playerID, 365*24*60*60, "/", "", secure, true)
+	return playerID
 }
 
 // getGameInstance is a helper function to extract, validate, and retrieve
-// a game session from the request context and session manager.
-func getGameInstance(c *gin.Context) (*game.Game, bool) {
+// a game session from the request context and session manager. The session
+// stays locked until the caller calls the returned unlock function.
+func getGameInstance(c *gin.Context) (*game.Game, func(), bool) {
 	sessionIDStr := c.Param("session_id")
 
 	// 1. Parse session UUID
 	sessionID, err := uuid.Parse(sessionIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
-		return nil, false
+		respondError(c, errInvalidSessionID, gin.H{"field": "session_id"})
+		return nil, nil, false
 	}
 
 	// 2. Retrieve instance from the package-level variable 'sm'
-	gameInstance, exists := sm.GetSession(sessionID)
+	gameInstance, unlock, exists := sm.LockSession(sessionID)
 	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
-		return nil, false
+		respondError(c, errSessionNotFound, gin.H{"field": "session_id"})
+		return nil, nil, false
 	}
 
-	return gameInstance, true
+	return gameInstance, unlock, true
 }
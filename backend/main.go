@@ -1,8 +1,11 @@
 package main
 
 import (
+	game "hangman/backend/game"
 	handlers "hangman/backend/handlers"
 	manager "hangman/backend/session"
+	"log"
+	"os"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -12,7 +15,22 @@ import (
 func main() {
 	router := gin.Default()
 	sessionManager := manager.NewSessionManager()
-	handlers.NewGameHandler(sessionManager)
+	wordSource, err := game.NewWordSourceFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure word source: %v", err)
+	}
+	if os.Getenv("WORD_SOURCE") == "" && os.Getenv("FIREBASE_PROJECT_ID") == "" {
+		log.Printf("WARNING: neither WORD_SOURCE nor FIREBASE_PROJECT_ID is set, serving words from the local word bank only")
+	}
+	log.Printf("Word source: %s", game.WordSourceName())
+	recentWords, err := game.NewRecentWordsFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure recent words: %v", err)
+	}
+	if err := game.LoadScoreWeightsFromEnv(); err != nil {
+		log.Fatalf("Failed to configure score weights: %v", err)
+	}
+	handlers.NewGameHandler(sessionManager, wordSource, recentWords)
 
 	// Configure CORS
 	router.Use(cors.New(cors.Config{
@@ -33,12 +51,17 @@ func main() {
 	router.POST("/api/game/new", handlers.NewGame)
 	router.POST("/api/game/:session_id/guess", handlers.MakeGuess)
 	router.POST("/api/game/:session_id/open_letter_attempts", handlers.OpenLetter)
+	router.POST("/api/game/:session_id/solve", handlers.Solve)
 	router.GET("/api/game/:session_id/state", handlers.GetState)
 	router.GET("/api/game/:session_id/hint", handlers.GetHint)
+	router.GET("/api/game/:session_id/moves", handlers.GetMoves)
+	router.POST("/api/game/:session_id/abandon", handlers.Abandon)
+	router.GET("/api/languages", handlers.GetLanguages)
+	router.GET("/api/categories", handlers.GetCategories)
+	router.GET("/api/words/pool", handlers.GetWordPoolStats)
+	router.GET("/api/words/health", handlers.GetWordSourceHealth)
 
-	router.NoRoute(func(ctx *gin.Context) {
-		ctx.JSON(404, gin.H{"code": "PAGE_NOT_FOUND", "message": "Page not found"})
-	})
+	router.NoRoute(handlers.NotFound)
 
 	router.Run(":8080")
 }
@@ -9,13 +9,19 @@ import (
 // SessionManager manages game sessions.
 type SessionManager struct {
 	mu       sync.RWMutex
-	sessions map[uuid.UUID]*game.Game
+	sessions map[uuid.UUID]*gameSession
+}
+
+// gameSession is a game with the lock that serializes the requests on it.
+type gameSession struct {
+	mu   sync.Mutex
+	game *game.Game
 }
 
 // NewSessionManager constructor creates a new SessionManager.
 func NewSessionManager() *SessionManager {
 	return &SessionManager{
-		sessions: make(map[uuid.UUID]*game.Game),
+		sessions: make(map[uuid.UUID]*gameSession),
 	}
 }
 
@@ -25,17 +31,22 @@ func (sm *SessionManager) CreateSession(game *game.Game) uuid.UUID {
 	defer sm.mu.Unlock()
 
 	sessionID := uuid.New()
-	sm.sessions[sessionID] = game
+	sm.sessions[sessionID] = &gameSession{game: game}
 	return sessionID
 }
 
-// GetSession retrieves a game session by its UUID.
-func (sm *SessionManager) GetSession(sessionID uuid.UUID) (*game.Game, bool) {
+// LockSession retrieves a game session by its UUID and locks it, so that only one request at a
+// time reads or changes the game. The caller must call the returned unlock function when done.
+func (sm *SessionManager) LockSession(sessionID uuid.UUID) (*game.Game, func(), bool) {
 	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	session, exists := sm.sessions[sessionID]
+	sm.mu.RUnlock()
+	if !exists {
+		return nil, nil, false
+	}
 
-	game, exists := sm.sessions[sessionID]
-	return game, exists
+	session.mu.Lock()
+	return session.game, session.mu.Unlock, true
 }
 
 // DeleteSession deletes a game session by its UUID.
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"hangman/backend/game"
 )
 
 // Firestore field structures for parsing response
@@ -39,7 +40,7 @@ func main() {
 		return
 	}
 
-	fmt.Printf("📡 Fetching random word from [%s] collection...\n", lang)
+	fmt.Printf("📡 Fetching random word from [%s] collection at %s...\n", lang, game.FirestoreBaseURL())
 
 	// 2. Implementation of the "Random Jump" logic
 	// We generate a random string to use as a starting point in the index
@@ -70,10 +71,22 @@ func main() {
 		},
 	}
 
-	url := fmt.Sprintf("https://firestore.googleapis.com/v1/%s:runQuery", fullParentPath)
+	url := fmt.Sprintf("%s%s:runQuery", game.FirestoreBaseURL(), fullParentPath)
+
+	auth, err := game.NewFirestoreAuthFromEnv()
+	if err != nil {
+		fmt.Printf("❌ Error loading service account key: %v\n", err)
+		return
+	}
 
 	jsonData, _ := json.Marshal(query)
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	req, _ := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	if err := auth.Authorize(req); err != nil {
+		fmt.Printf("❌ Authentication error: %v\n", err)
+		return
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		fmt.Printf("❌ Connection error: %v\n", err)
 		return
@@ -81,7 +94,7 @@ func main() {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("❌ Database error: %d. Check if your Security Rules allow public read.\n", resp.StatusCode)
+		fmt.Printf("❌ Database error: %d. Check your service account key or that your Security Rules allow public read.\n", resp.StatusCode)
 		return
 	}
 
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"hangman/backend/firestorefake"
+	"hangman/backend/game"
+)
+
+func main() {
+	// 1. Parse flags
+	addr := flag.String("addr", "localhost:8090", "address to listen on")
+	projectID := flag.String("project", "demo-hangman", "Firebase project ID the words are stored under")
+	appID := flag.String("app", "go-hangman-v1", "app ID used in the document path")
+	wordsFilePath := flag.String("words", "", "optional words.json to preload (same format as the seeder)")
+	requireAuth := flag.Bool("auth", false, "require Bearer tokens issued by the /token endpoint")
+	flag.Parse()
+
+	server := firestorefake.New()
+	if *requireAuth {
+		server.RequireAuth(nil)
+	}
+
+	// 2. Preload the word bank, if any
+	if *wordsFilePath != "" {
+		fileData, err := os.ReadFile(*wordsFilePath)
+		if err != nil {
+			fmt.Printf("❌ Error reading file: %v\n", err)
+			return
+		}
+		wordBank, err := game.ParseWordBank(fileData)
+		if err != nil {
+			fmt.Printf("❌ Error parsing JSON: %v\n", err)
+			return
+		}
+		parentPath := fmt.Sprintf("%s/artifacts/%s/public/data", firestorefake.DocumentsRoot(*projectID), *appID)
+		for lang, words := range wordBank {
+			for _, w := range words {
+				fields := map[string]string{
+					"text": strings.ToUpper(strings.TrimSpace(w.Text)),
+					"hint": w.Hint,
+				}
+				if w.Category != "" {
+					fields["category"] = strings.ToLower(strings.TrimSpace(w.Category))
+				}
+				server.AddDocument(parentPath+"/"+lang, "", fields)
+			}
+			fmt.Printf("✅ [%s] Preloaded %d words\n", lang, len(words))
+		}
+	}
+
+	// 3. Serve
+	fmt.Printf("🚀 Fake Firestore listening on http://%s\n", *addr)
+	fmt.Printf("   export FIRESTORE_BASE_URL=http://%s/v1/ FIREBASE_PROJECT_ID=%s\n", *addr, *projectID)
+	if *requireAuth {
+		fmt.Printf("   Token endpoint: http://%s%s (use it as token_uri in your service account key)\n", *addr, firestorefake.TokenPath)
+	}
+	if err := http.ListenAndServe(*addr, server); err != nil {
+		fmt.Printf("❌ Server error: %v\n", err)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,14 +11,9 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"hangman/backend/game"
 )
 
-// WordEntry represents a single word and its hint
-type WordEntry struct {
-	Text string `json:"text"`
-	Hint string `json:"hint"`
-}
-
 // FirestoreResponse handles the JSON structure for GET requests
 type FirestoreResponse struct {
 	Documents []struct {
@@ -44,8 +40,10 @@ func main() {
 	if wordsFilePath == "" {
 		wordsFilePath = "words.json"
 	}
+	// When SQLITE_PATH is set, words are imported into that SQLite store instead of Firestore.
+	sqlitePath := os.Getenv("SQLITE_PATH")
 
-	if projectID == "" {
+	if projectID == "" && sqlitePath == "" {
 		fmt.Println("❌ Error: FIREBASE_PROJECT_ID is not set.")
 		return
 	}
@@ -57,8 +55,8 @@ func main() {
 		return
 	}
 
-	var wordBank map[string][]WordEntry
-	if err := json.Unmarshal(fileData, &wordBank); err != nil {
+	wordBank, err := game.ParseWordBank(fileData)
+	if err != nil {
 		fmt.Printf("❌ Error parsing JSON: %v\n", err)
 		return
 	}
@@ -78,10 +76,27 @@ func main() {
 		}
 	}
 
-	// 5. Database Seed Logic with Remote Duplicate Check
-	baseURL := "https://firestore.googleapis.com/v1/projects/" + projectID + "/databases/(default)/documents/artifacts/" + appId + "/public/data"
+	// 5. Local Seed Logic for the SQLite word store
+	if sqlitePath != "" {
+		seedSQLite(sqlitePath, wordBank)
+		return
+	}
+
+	// 6. Database Seed Logic with Remote Duplicate Check
+	baseURL := game.FirestoreBaseURL() + "projects/" + projectID + "/databases/(default)/documents/artifacts/" + appId + "/public/data"
 	client := &http.Client{Timeout: 10 * time.Second}
 
+	// Sign requests with the service account key when one is available, so Firestore
+	// security rules don't have to allow public writes.
+	auth, err := game.NewFirestoreAuthFromEnv()
+	if err != nil {
+		fmt.Printf("❌ Error loading service account key: %v\n", err)
+		return
+	}
+	if auth == nil {
+		fmt.Println("⚠️  No service account key found, sending unauthenticated requests.")
+	}
+
 	fmt.Println("🚀 Starting synchronized database seed...")
 
 	for lang, words := range wordBank {
@@ -90,7 +105,7 @@ func main() {
 		// A. Fetch existing words from Firestore to avoid duplicates
 		fmt.Printf("📡 Checking remote duplicates for [%s]...\n", lang)
 		existingWords := make(map[string]bool)
-		resp, err := client.Get(targetURL)
+		resp, err := sendRequest(client, auth, http.MethodGet, targetURL, nil)
 		if err == nil && resp.StatusCode == http.StatusOK {
 			var remoteData FirestoreResponse
 			if err := json.NewDecoder(resp.Body).Decode(&remoteData); err == nil {
@@ -110,15 +125,17 @@ func main() {
 				continue
 			}
 
-			payload := map[string]any{
-				"fields": map[string]any{
-					"text": map[string]any{"stringValue": cleanText},
-					"hint": map[string]any{"stringValue": w.Hint},
-				},
+			fields := map[string]any{
+				"text": map[string]any{"stringValue": cleanText},
+				"hint": map[string]any{"stringValue": w.Hint},
+			}
+			if category := strings.ToLower(strings.TrimSpace(w.Category)); category != "" {
+				fields["category"] = map[string]any{"stringValue": category}
 			}
+			payload := map[string]any{"fields": fields}
 
 			jsonData, _ := json.Marshal(payload)
-			postResp, err := http.Post(targetURL, "application/json", bytes.NewBuffer(jsonData))
+			postResp, err := sendRequest(client, auth, http.MethodPost, targetURL, jsonData)
 			if err != nil {
 				fmt.Printf("❌ [%s] Network error sending %s: %v\n", lang, cleanText, err)
 				continue
@@ -135,3 +152,39 @@ func main() {
 
 	fmt.Println("\n✨ Seed complete.")
 }
+
+// seedSQLite imports the word bank into the SQLite word store at path, skipping words it already holds.
+func seedSQLite(path string, wordBank map[string][]game.WordEntry) {
+	fmt.Printf("🚀 Importing word bank into SQLite store %s...\n", path)
+
+	store, err := game.OpenSQLiteSource(path)
+	if err != nil {
+		fmt.Printf("❌ Error opening SQLite store: %v\n", err)
+		return
+	}
+	defer store.Close()
+
+	imported, err := store.ImportWordBank(context.Background(), wordBank)
+	if err != nil {
+		fmt.Printf("❌ Error importing words: %v\n", err)
+		return
+	}
+	total, _ := store.CountWords(context.Background())
+	fmt.Printf("✅ Imported %d new words (%d in store)\n", imported, total)
+	fmt.Println("\n✨ Seed complete.")
+}
+
+// sendRequest sends a Firestore request, signed with the service account token if auth is not nil.
+func sendRequest(client *http.Client, auth *game.TokenSource, method, url string, body []byte) (*http.Response, error) {
+	request, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if err := auth.Authorize(request); err != nil {
+		return nil, err
+	}
+	return client.Do(request)
+}
@@ -85,7 +85,7 @@ function App() {
         // Language-specific validation
         if (language === "en" && /^[a-z]$/.test(key)) {
           makeGuess(key);
-        } else if (language === "uk" && (/^[\u0400-\u04FF]$/.test(key) || key === "'")) {
+        } else if (language === "uk" && /^[\u0400-\u04FF]$/.test(key)) {
           makeGuess(key);
         } else if (language === "pl" && /^[a-ząćęłńóśźż]$/.test(key)) {
           makeGuess(key);
@@ -27,7 +27,7 @@ export const useGame = () => {
             setSessionId(response.session_id);
             setLanguage(lang);
             setDifficulty(diff);
-            setCurrentWord("_ ".repeat(response.word_length).trim());
+            setCurrentWord(response.current_word);
             setTriesLeft(response.max_attempts);
             setGuessedLetters([]);
             setIsGameOver(false);
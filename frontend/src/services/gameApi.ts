@@ -9,10 +9,16 @@ import type {
 } from "../types/game";
 
 const API_BASE_URL = import.meta.env.VITE_API_URL || "http://localhost:8080";
+const PLAYER_ID_KEY = "hangman_player_id";
 
 // Service functions for interacting with the Hangman game API
+
+// The backend avoids repeating recent words per player, so the player ID it issues is kept in
+// localStorage and sent with every new game (the API is cross-site, so its cookie doesn't stick).
 export const createNewGame = async (request: NewGameRequest): Promise<NewGameResponse> => {
-    const response = await axios.post<NewGameResponse>(`${API_BASE_URL}/api/game/new`, request);
+    const playerId = localStorage.getItem(PLAYER_ID_KEY) ?? undefined;
+    const response = await axios.post<NewGameResponse>(`${API_BASE_URL}/api/game/new`, { player_id: playerId, ...request });
+    localStorage.setItem(PLAYER_ID_KEY, response.data.player_id);
     return response.data;
 };
 
@@ -2,13 +2,16 @@
 export type NewGameRequest = {
     language: Language
     difficulty: Difficulty
+    player_id?: string
 }
 
 export type NewGameResponse = {
     session_id: string
     word_length: number
+    current_word: string
     max_attempts: number
     open_letter_attempts: number
+    player_id: string
 }
 
 export type GuessRequest = {